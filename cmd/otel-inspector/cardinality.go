@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jimschubert/otel-relay/internal/analyzer"
+	"github.com/jimschubert/otel-relay/internal/formatter"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+var (
+	_ formatter.Formatter = (*cardinalityFormatter)(nil)
+)
+
+// cardinalityKeys is how many of each metric's attribute keys the report lists.
+const cardinalityKeys = 3
+
+// cardinalityFormatter prints a warning as metrics cross the cardinality threshold, and reports the metrics with the
+// most series on exit.
+type cardinalityFormatter struct {
+	cardinality *analyzer.Cardinality
+	top         int
+}
+
+func newCardinalityFormatter(threshold, top int) *cardinalityFormatter {
+	return &cardinalityFormatter{
+		cardinality: analyzer.NewCardinality(threshold),
+		top:         top,
+	}
+}
+
+func (c *cardinalityFormatter) FormatTrace(*collectortrace.ExportTraceServiceRequest) string {
+	return ""
+}
+
+func (c *cardinalityFormatter) FormatMetric(req *collectormetrics.ExportMetricsServiceRequest) string {
+	var buf strings.Builder
+	for _, f := range analyzer.AnalyzeMetrics(c.cardinality, req) {
+		fmt.Fprintf(&buf, "%s %s\n", formatter.SeverityIcon(f.Severity), describeFinding(f))
+	}
+	return buf.String()
+}
+
+func (c *cardinalityFormatter) FormatLog(*collectorlogs.ExportLogsServiceRequest) string {
+	return ""
+}
+
+func (c *cardinalityFormatter) printReport(w io.Writer) {
+	threshold := c.cardinality.Threshold()
+	top := c.cardinality.Top(c.top)
+
+	fmt.Fprintf(w, "\n📏 CARDINALITY (threshold %d series)\n", threshold)
+	if len(top) == 0 {
+		fmt.Fprintf(w, "└─ No metrics\n")
+		return
+	}
+
+	names := make([]string, 0, len(top))
+	for _, m := range top {
+		names = append(names, m.Name)
+	}
+	width := maxWidth(names)
+
+	for idx, m := range top {
+		marker, prefix := branch(idx, len(top)+1, "")
+		warning := ""
+		if m.Series > threshold {
+			warning = " " + formatter.SeverityIcon(analyzer.SeverityWarning)
+		}
+		fmt.Fprintf(w, "%s %-*s %7d series  +%d in the last minute%s\n", marker, width, m.Name, m.Series, m.Growth, warning)
+
+		keys := m.Keys[:min(len(m.Keys), cardinalityKeys)]
+		for keyIdx, key := range keys {
+			keyMarker, _ := branch(keyIdx, len(keys), prefix)
+			fmt.Fprintf(w, "%s%s %s: %d values\n", prefix, keyMarker, key.Key, key.Values)
+		}
+	}
+	fmt.Fprintf(w, "└─────────────────────────────────────\n")
+}
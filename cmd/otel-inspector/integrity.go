@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jimschubert/otel-relay/internal/analyzer"
+	"github.com/jimschubert/otel-relay/internal/assembler"
+	"github.com/jimschubert/otel-relay/internal/formatter"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+var (
+	_ formatter.Formatter = (*integrityFormatter)(nil)
+)
+
+// integrityFormatter reassembles traces and prints each integrity finding as its trace completes, counting findings
+// by rule and service for the summary.
+type integrityFormatter struct {
+	integrity *analyzer.Integrity
+	assembler *assembler.Assembler
+
+	mu        sync.Mutex
+	traces    int
+	flagged   int
+	byRule    map[string]int
+	byService map[string]int
+}
+
+// newIntegrityFormatter prints findings to out from the assembler's goroutine, so out must be safe to share with the
+// event loop.
+func newIntegrityFormatter(out io.Writer, window, skew time.Duration) *integrityFormatter {
+	i := &integrityFormatter{
+		integrity: analyzer.NewIntegrity(skew),
+		byRule:    make(map[string]int),
+		byService: make(map[string]int),
+	}
+	i.assembler = assembler.New(window, func(trace *assembler.Trace) {
+		fmt.Fprint(out, i.check(trace))
+	})
+	return i
+}
+
+func (i *integrityFormatter) FormatTrace(req *collectortrace.ExportTraceServiceRequest) string {
+	i.assembler.Add(req)
+	return ""
+}
+
+func (i *integrityFormatter) FormatMetric(*collectormetrics.ExportMetricsServiceRequest) string {
+	return ""
+}
+
+func (i *integrityFormatter) FormatLog(*collectorlogs.ExportLogsServiceRequest) string {
+	return ""
+}
+
+func (i *integrityFormatter) check(trace *assembler.Trace) string {
+	findings := i.integrity.Trace(trace)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.traces++
+	if len(findings) > 0 {
+		i.flagged++
+	}
+
+	var out strings.Builder
+	for _, f := range findings {
+		i.byRule[f.Rule]++
+		i.byService[serviceOrUnknown(f.Service)]++
+		fmt.Fprintf(&out, "%s trace %x %s\n", formatter.SeverityIcon(f.Severity), trace.TraceID, describeFinding(f))
+	}
+	return out.String()
+}
+
+// Close checks the traces still being assembled.
+func (i *integrityFormatter) Close() {
+	i.assembler.Close()
+}
+
+func (i *integrityFormatter) printSummary(w io.Writer) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	fmt.Fprintf(w, "\n🔗 TRACE INTEGRITY SUMMARY\n")
+	fmt.Fprintf(w, "├─ Traces: %d (%d with findings)\n", i.traces, i.flagged)
+	if len(i.byRule) == 0 {
+		fmt.Fprintf(w, "└─ No findings\n")
+		return
+	}
+
+	count := func(n int) int { return n }
+	fmt.Fprintf(w, "├─ By rule:\n")
+	for _, rule := range sortedByCount(i.byRule, count) {
+		fmt.Fprintf(w, "│  ├─ %5d× %s\n", i.byRule[rule], rule)
+	}
+	fmt.Fprintf(w, "├─ By service:\n")
+	for _, service := range sortedByCount(i.byService, count) {
+		fmt.Fprintf(w, "│  ├─ %s: %d\n", service, i.byService[service])
+	}
+	fmt.Fprintf(w, "└─────────────────────────────────────\n")
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jimschubert/otel-relay/internal/analyzer"
+	"github.com/jimschubert/otel-relay/internal/formatter"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+var (
+	_ formatter.Formatter = (*lintFormatter)(nil)
+)
+
+// lintFormatter prints each distinct semantic convention finding the first time it is seen, and counts every occurrence.
+type lintFormatter struct {
+	semconv *analyzer.Semconv
+
+	mu     sync.Mutex
+	counts map[analyzer.Finding]int
+}
+
+func newLintFormatter() *lintFormatter {
+	return &lintFormatter{
+		semconv: analyzer.NewSemconv(),
+		counts:  make(map[analyzer.Finding]int),
+	}
+}
+
+func (l *lintFormatter) FormatTrace(req *collectortrace.ExportTraceServiceRequest) string {
+	return l.record(analyzer.AnalyzeTraces(l.semconv, req))
+}
+
+func (l *lintFormatter) FormatMetric(req *collectormetrics.ExportMetricsServiceRequest) string {
+	return l.record(analyzer.AnalyzeMetrics(l.semconv, req))
+}
+
+func (l *lintFormatter) FormatLog(req *collectorlogs.ExportLogsServiceRequest) string {
+	return l.record(analyzer.AnalyzeLogs(l.semconv, req))
+}
+
+func (l *lintFormatter) record(findings []analyzer.Finding) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var buf strings.Builder
+	for _, f := range findings {
+		if l.counts[f] == 0 {
+			fmt.Fprintf(&buf, "%s %s\n", formatter.SeverityIcon(f.Severity), describeFinding(f))
+		}
+		l.counts[f]++
+	}
+	return buf.String()
+}
+
+func (l *lintFormatter) printSummary(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fmt.Fprintf(w, "\n🔍 LINT SUMMARY\n")
+	if len(l.counts) == 0 {
+		fmt.Fprintf(w, "└─ No findings\n")
+		return
+	}
+
+	findings := slices.SortedFunc(maps.Keys(l.counts), func(a, b analyzer.Finding) int {
+		return cmp.Or(
+			cmp.Compare(b.Severity, a.Severity),
+			cmp.Compare(l.counts[b], l.counts[a]),
+			cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.Service, b.Service),
+			cmp.Compare(a.Target, b.Target),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+
+	bySeverity := make(map[analyzer.Severity]int)
+	byService := make(map[string]int)
+	for _, f := range findings {
+		bySeverity[f.Severity] += l.counts[f]
+		byService[serviceOrUnknown(f.Service)] += l.counts[f]
+	}
+
+	fmt.Fprintf(w, "├─ Findings:\n")
+	for _, f := range findings {
+		fmt.Fprintf(w, "│  ├─ %5d× %s %s\n", l.counts[f], formatter.SeverityIcon(f.Severity), describeFinding(f))
+	}
+
+	fmt.Fprintf(w, "├─ By service:\n")
+	for _, service := range slices.Sorted(maps.Keys(byService)) {
+		fmt.Fprintf(w, "│  ├─ %s: %d\n", service, byService[service])
+	}
+
+	fmt.Fprintf(w, "└─ Totals: %d errors, %d warnings, %d info\n",
+		bySeverity[analyzer.SeverityError],
+		bySeverity[analyzer.SeverityWarning],
+		bySeverity[analyzer.SeverityInfo],
+	)
+}
+
+func describeFinding(f analyzer.Finding) string {
+	return fmt.Sprintf("[%s] %s: %s (%s)", serviceOrUnknown(f.Service), f.Target, f.Message, f.Rule)
+}
+
+func serviceOrUnknown(service string) string {
+	if service == "" {
+		return "<no service.name>"
+	}
+	return service
+}
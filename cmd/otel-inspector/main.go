@@ -7,16 +7,19 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/eiannone/keyboard"
+	"github.com/jimschubert/otel-relay/internal/analyzer"
 	"github.com/jimschubert/otel-relay/internal/formatter"
 	"github.com/jimschubert/otel-relay/proto/inspector"
 	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
 	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"golang.org/x/term"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/proto"
@@ -29,15 +32,40 @@ var (
 )
 
 var CLI struct {
-	Socket  string           `short:"s" default:"/tmp/otel-relay.sock" help:"Path to Unix domain socket to read from"`
-	Verbose bool             `help:"Verbose output (show all attributes)"`
-	Version kong.VersionFlag `short:"v" help:"Print version information"`
+	Socket       string           `short:"s" default:"/tmp/otel-relay.sock" help:"Path to Unix domain socket to read from"`
+	Verbose      bool             `help:"Verbose output (show all attributes)"`
+	Semconv      bool             `help:"Show semantic convention findings inline"`
+	Format       string           `short:"f" enum:"tree,compact,logfmt,csv,jaeger,zipkin" default:"tree" help:"Output format: tree, compact for one line per span, log record and data point, logfmt, csv, or jaeger or zipkin JSON of the spans (written on exit)"`
+	Columns      []string         `optional:"" sep:"," placeholder:"<key,...>" help:"Attributes to show as columns in the compact format (default: common HTTP, RPC and database attributes)"`
+	Color        string           `enum:"auto,always,never" default:"auto" help:"Colorize output: auto, always or never. Auto disables color when stdout isn't a terminal or NO_COLOR is set"`
+	Template     string           `optional:"" xor:"template" placeholder:"<template>" help:"Render each span, log record and data point with a Go text/template, e.g. '{{.Resource.service.name}} {{.Name}} {{.Duration}}'"`
+	TemplateFile string           `optional:"" xor:"template" type:"existingfile" placeholder:"<path>" help:"Read the --template from a file"`
+	Waterfall    bool             `help:"Reassemble spans into traces and print each trace as a waterfall"`
+	Window       time.Duration    `default:"5s" help:"How long to wait for a trace's spans to arrive before printing its waterfall"`
+	Integrity    bool             `help:"Check reassembled traces for orphans, duplicate span IDs, clock skew and broken propagation, shown inline in the waterfall (implies --waterfall)"`
+	Skew         time.Duration    `default:"100ms" help:"How far a child span may start before, or end after, its parent before it's reported as clock skew"`
+	Version      kong.VersionFlag `short:"v" help:"Print version information"`
+
+	Stream  struct{} `cmd:"" default:"1" hidden:"" help:"Print signals as they arrive (default)"`
+	Lint    struct{} `cmd:"" help:"Check signals against OpenTelemetry semantic conventions, summarizing findings on exit"`
+	Tui     struct{} `cmd:"" help:"Browse signals in a full-screen terminal UI"`
+	Summary struct{} `cmd:"" help:"Show live counts, error rates and latency percentiles per service instead of each signal"`
+
+	Cardinality struct {
+		Threshold int `default:"1000" help:"Warn when a metric has more than this many unique attribute sets"`
+		Top       int `default:"10" help:"How many metrics to report on exit"`
+	} `cmd:"" help:"Track unique attribute sets per metric, warning as metrics cross a threshold and reporting the top offenders on exit"`
+	IntegrityCmd struct{} `cmd:"" name:"integrity" help:"Check reassembled traces for orphans, duplicate span IDs, clock skew and broken propagation, summarizing findings on exit"`
+	Sizes        struct{} `cmd:"" help:"Report which services, signals and attributes make exports large, and any oversized attribute values or span events"`
+	Trace        struct {
+		ID string `arg:"" name:"trace-id" help:"Trace ID, as 32 hex characters"`
+	} `cmd:"" help:"Look up one trace's spans and logs in the relay's retained history"`
 }
 
 func main() {
 	formattedVersion := fmt.Sprintf("%s (%s)", version, commit)
 
-	kong.Parse(&CLI,
+	ctx := kong.Parse(&CLI,
 		kong.Name(programName),
 		kong.Description("Inspect OTLP signals emitted from otel-relay"),
 		kong.UsageOnError(),
@@ -46,21 +74,141 @@ func main() {
 		},
 	)
 
-	if err := run(); err != nil {
+	if ctx.Command() == "stream" {
+		ctx.FatalIfErrorf(checkOutputFlags())
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(ctx.Command(), "lint"):
+		lint := newLintFormatter()
+		err = run(lint)
+		lint.printSummary(os.Stdout)
+	case strings.HasPrefix(ctx.Command(), "summary"):
+		summary := newSummaryFormatter()
+		stop := func() {}
+		if term.IsTerminal(int(os.Stdout.Fd())) {
+			stop = summary.refresh(os.Stdout, time.Second)
+		}
+		err = run(summary)
+		stop()
+		summary.print(os.Stdout)
+	case strings.HasPrefix(ctx.Command(), "cardinality"):
+		cardinality := newCardinalityFormatter(CLI.Cardinality.Threshold, CLI.Cardinality.Top)
+		err = run(cardinality)
+		cardinality.printReport(os.Stdout)
+	case strings.HasPrefix(ctx.Command(), "integrity"):
+		integrity := newIntegrityFormatter(stdout, CLI.Window, CLI.Skew)
+		err = run(integrity)
+		integrity.Close()
+		integrity.printSummary(stdout)
+	case strings.HasPrefix(ctx.Command(), "trace"):
+		err = runTrace(os.Stdout, CLI.Trace.ID)
+	case strings.HasPrefix(ctx.Command(), "sizes"):
+		err = runSizes(os.Stdout)
+	case strings.HasPrefix(ctx.Command(), "tui"):
+		err = runTUI()
+	case CLI.Template != "" || CLI.TemplateFile != "":
+		var form formatter.Formatter
+		form, err = newTemplateFormatter()
+		if err == nil {
+			err = run(form)
+		}
+	case CLI.Format == "compact":
+		err = run(formatter.NewCompactFormatter(CLI.Columns, useColor()))
+	case CLI.Format == "logfmt":
+		err = run(formatter.NewLogfmtFormatter())
+	case CLI.Format == "csv":
+		table := formatter.NewCSVFormatter()
+		err = run(table)
+		if flushErr := table.Flush(os.Stdout); err == nil {
+			err = flushErr
+		}
+	case CLI.Format == "jaeger" || CLI.Format == "zipkin":
+		file := newTraceFileFormatter()
+		err = run(file)
+		if flushErr := file.Flush(os.Stdout); err == nil {
+			err = flushErr
+		}
+	default:
+		tree := formatter.NewTreeFormatter(CLI.Verbose, treeOptions()...)
+		if CLI.Waterfall || CLI.Integrity {
+			waterfall := newWaterfallFormatter(stdout, tree, CLI.Window)
+			err = run(waterfall)
+			waterfall.Close()
+		} else {
+			err = run(tree)
+		}
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
 }
 
-func run() error {
+// checkOutputFlags rejects combinations the default mode would otherwise ignore: --template replaces --format, and
+// --waterfall, --integrity and --semconv only apply to the tree format.
+func checkOutputFlags() error {
+	template := CLI.Template != "" || CLI.TemplateFile != ""
+	if template && CLI.Format != "tree" {
+		return fmt.Errorf("--template can't be combined with --format %s", CLI.Format)
+	}
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{{"--waterfall", CLI.Waterfall}, {"--integrity", CLI.Integrity}, {"--semconv", CLI.Semconv}} {
+		switch {
+		case flag.set && template:
+			return fmt.Errorf("%s only applies to the tree format, not --template", flag.name)
+		case flag.set && CLI.Format != "tree":
+			return fmt.Errorf("%s only applies to the tree format, not --format %s", flag.name, CLI.Format)
+		}
+	}
+	return nil
+}
+
+// treeOptions applies the terminal options, and the analyzers enabled by --semconv and --integrity.
+func treeOptions() []formatter.TreeOption {
+	opts := terminalOptions()
+	if CLI.Semconv {
+		opts = append(opts, formatter.WithAnalyzers(analyzer.NewSemconv()))
+	}
+	if CLI.Integrity {
+		opts = append(opts, formatter.WithTraceAnalyzers(analyzer.NewIntegrity(CLI.Skew)))
+	}
+	return opts
+}
+
+// newTraceFileFormatter returns the formatter for --format jaeger or zipkin, or nil for any other format.
+func newTraceFileFormatter() *formatter.TraceFileFormatter {
+	switch CLI.Format {
+	case "jaeger":
+		return formatter.NewJaegerFormatter()
+	case "zipkin":
+		return formatter.NewZipkinFormatter()
+	default:
+		return nil
+	}
+}
+
+func newTemplateFormatter() (*formatter.TemplateFormatter, error) {
+	text := CLI.Template
+	if CLI.TemplateFile != "" {
+		content, err := os.ReadFile(CLI.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template file: %w", err)
+		}
+		text = string(content)
+	}
+	return formatter.NewTemplateFormatter(text)
+}
+
+func run(form formatter.Formatter) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	conn, err := grpc.NewClient(
-		"unix://"+CLI.Socket,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := connect()
 	if err != nil {
-		return fmt.Errorf("failed to connect to socket %s: %w", CLI.Socket, err)
+		return err
 	}
 	defer conn.Close()
 
@@ -70,14 +218,12 @@ func run() error {
 		return fmt.Errorf("failed to create stream: %w", err)
 	}
 
-	form := formatter.NewTreeFormatter(CLI.Verbose)
-
 	if err := keyboard.Open(); err != nil {
 		log.Printf("Warning: keyboard input disabled: %v", err)
 	} else {
 		defer keyboard.Close()
 		log.Println("Interactive mode enabled. Press 'v' to toggle verbose, 'q' to quit")
-		go handleKeyboard(client, form)
+		go handleKeyboard(client, form, cancel)
 	}
 
 	sigChan := make(chan os.Signal, 1)
@@ -99,13 +245,33 @@ func run() error {
 		}
 
 		output := formatEvent(event, form)
+		if violations := formatViolations(event.Violations); violations != "" {
+			switch form.(type) {
+			case *formatter.TreeFormatter, *waterfallFormatter:
+				output = violations + output
+			default:
+				// keep machine-readable formats and the summary screen parseable
+				fmt.Fprint(os.Stderr, violations)
+			}
+		}
 		if output != "" {
-			fmt.Print(output)
+			fmt.Fprint(stdout, output)
 		}
 	}
 }
 
-func handleKeyboard(client inspector.InspectorServiceClient, form *formatter.TreeFormatter) {
+func connect() (*grpc.ClientConn, error) {
+	conn, err := grpc.NewClient(
+		"unix://"+CLI.Socket,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to socket %s: %w", CLI.Socket, err)
+	}
+	return conn, nil
+}
+
+func handleKeyboard(client inspector.InspectorServiceClient, form formatter.Formatter, quit context.CancelFunc) {
 	verbose := CLI.Verbose
 	for {
 		char, key, err := keyboard.GetKey()
@@ -114,12 +280,13 @@ func handleKeyboard(client inspector.InspectorServiceClient, form *formatter.Tre
 		}
 
 		if key == keyboard.KeyCtrlC || char == 'q' {
-			os.Exit(0)
+			quit()
+			return
 		}
 
-		if char == 'v' {
+		if tree, ok := form.(interface{ SetVerbose(bool) }); ok && char == 'v' {
 			verbose = !verbose
-			form.SetVerbose(verbose)
+			tree.SetVerbose(verbose)
 			if verbose {
 				log.Println("Verbose mode enabled")
 			} else {
@@ -133,7 +300,7 @@ func handleKeyboard(client inspector.InspectorServiceClient, form *formatter.Tre
 			// can't defer because of for loop
 			canceler()
 			if err != nil {
-				fmt.Printf("Error fetching stats: %v\n", err)
+				fmt.Fprintf(stdout, "Error fetching stats: %v\n", err)
 				continue
 			}
 
@@ -143,7 +310,7 @@ func handleKeyboard(client inspector.InspectorServiceClient, form *formatter.Tre
 }
 
 func printStats(stats *inspector.StatsResponse) {
-	fmt.Printf("Stats: Uptime=%s Readers=%d, Writers=%d, Total Traces=%d, Metrics=%d, Logs=%d, Total Bytes=%d\n",
+	fmt.Fprintf(stdout, "Stats: Uptime=%s Readers=%d, Writers=%d, Total Traces=%d, Metrics=%d, Logs=%d, Total Bytes=%d\n",
 		time.Duration(stats.GetUptimeSeconds())*time.Second,
 		stats.GetActiveReaders(),
 		stats.GetActiveWriters(),
@@ -184,3 +351,21 @@ func formatEvent(event *inspector.TelemetryEvent, form formatter.Formatter) stri
 		return ""
 	}
 }
+
+// formatViolations renders the OTLP spec violations the relay flagged on an event, if any.
+func formatViolations(violations []*inspector.Violation) string {
+	if len(violations) == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "\n🚫 OTLP SPEC VIOLATIONS (%d)\n", len(violations))
+	for idx, v := range violations {
+		branch := "├─"
+		if idx == len(violations)-1 {
+			branch = "└─"
+		}
+		fmt.Fprintf(&buf, "%s [%s] %s: %s (%s)\n", branch, serviceOrUnknown(v.Service), v.Target, v.Message, v.Rule)
+	}
+	return buf.String()
+}
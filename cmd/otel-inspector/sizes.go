@@ -0,0 +1,128 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jimschubert/otel-relay/proto/inspector"
+)
+
+// sizeReportTop is how many attributes and oversized items the report lists.
+const sizeReportTop = 20
+
+// runSizes fetches the daemon's byte accounting and prints which services, signals and attributes make exports large.
+func runSizes(w io.Writer) error {
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := inspector.NewInspectorServiceClient(conn).GetStats(ctx, &inspector.StatsRequest{Breakdown: true})
+	if err != nil {
+		return fmt.Errorf("failed to fetch stats: %w", err)
+	}
+	printSizes(w, stats)
+	return nil
+}
+
+func printSizes(w io.Writer, stats *inspector.StatsResponse) {
+	var total uint64
+	for _, s := range stats.Services {
+		total += s.Bytes
+	}
+
+	fmt.Fprintf(w, "📦 PAYLOAD SIZES (%s in %d requests, uptime %s)\n",
+		formatBytes(total),
+		stats.GetTracesObserved()+stats.GetMetricsObserved()+stats.GetLogsObserved(),
+		time.Duration(stats.GetUptimeSeconds())*time.Second,
+	)
+	if len(stats.Services) == 0 {
+		fmt.Fprintf(w, "└─ Nothing observed yet\n")
+		return
+	}
+
+	printServiceSizes(w, stats.Services, total)
+
+	attributes := stats.Attributes[:min(len(stats.Attributes), sizeReportTop)]
+	fmt.Fprintf(w, "├─ Top attributes:\n")
+	for idx, a := range attributes {
+		marker, _ := branch(idx, len(attributes), "")
+		fmt.Fprintf(w, "│  %s %s [%s]: %s (%s), %d× avg %s, max %s\n",
+			marker, a.Key, serviceOrUnknown(a.Service),
+			formatBytes(a.Bytes), percent(int(a.Bytes), int(total)),
+			a.Count, formatBytes(a.Bytes/max(a.Count, 1)), formatBytes(a.MaxBytes),
+		)
+	}
+
+	oversized := stats.Oversized[:min(len(stats.Oversized), sizeReportTop)]
+	fmt.Fprintf(w, "├─ Oversized attribute values and span events:\n")
+	if len(oversized) == 0 {
+		fmt.Fprintf(w, "│  └─ None\n")
+	}
+	for idx, o := range oversized {
+		marker, _ := branch(idx, len(oversized), "")
+		fmt.Fprintf(w, "│  %s [%s] %s: max %s, %d×\n", marker, serviceOrUnknown(o.Service), o.Target, formatBytes(o.MaxBytes), o.Count)
+	}
+	fmt.Fprintf(w, "└─────────────────────────────────────\n")
+}
+
+// printServiceSizes lists each service's bytes, largest first, with its split across signals.
+func printServiceSizes(w io.Writer, entries []*inspector.ServiceBytes, total uint64) {
+	bytes := make(map[string]uint64)
+	bySignal := make(map[string][]string)
+	var services []string
+	for _, s := range entries {
+		service := serviceOrUnknown(s.Service)
+		if _, ok := bytes[service]; !ok {
+			services = append(services, service)
+		}
+		bytes[service] += s.Bytes
+		bySignal[service] = append(bySignal[service], fmt.Sprintf("%s %s", signalName(s.Type), formatBytes(s.Bytes)))
+	}
+	slices.SortStableFunc(services, func(a, b string) int {
+		return cmp.Compare(bytes[b], bytes[a])
+	})
+
+	width := maxWidth(services)
+	fmt.Fprintf(w, "├─ By service:\n")
+	for idx, service := range services {
+		marker, _ := branch(idx, len(services), "")
+		fmt.Fprintf(w, "│  %s %-*s %10s %6s  %s\n", marker, width, service,
+			formatBytes(bytes[service]), percent(int(bytes[service]), int(total)), strings.Join(bySignal[service], ", "))
+	}
+}
+
+func signalName(t inspector.TelemetryType) string {
+	switch t {
+	case inspector.TelemetryType_TELEMETRY_TYPE_TRACE:
+		return "traces"
+	case inspector.TelemetryType_TELEMETRY_TYPE_METRIC:
+		return "metrics"
+	case inspector.TelemetryType_TELEMETRY_TYPE_LOG:
+		return "logs"
+	default:
+		return "unknown"
+	}
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
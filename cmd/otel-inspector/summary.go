@@ -0,0 +1,383 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jimschubert/otel-relay/internal/analyzer"
+	"github.com/jimschubert/otel-relay/internal/formatter"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+var (
+	_ formatter.Formatter = (*summaryFormatter)(nil)
+)
+
+// maxSummaryDurations is how many of the most recent durations per span name the percentiles are computed from.
+const maxSummaryDurations = 1000
+
+// summaryFormatter aggregates signals instead of printing them: span counts, error rates and duration percentiles per
+// span name, log counts per severity, and data point counts per metric, each grouped by service.
+type summaryFormatter struct {
+	mu      sync.Mutex
+	started time.Time
+	spans   map[string]map[string]*spanStats
+	logs    map[string]map[string]int
+	metrics map[string]map[string]*metricStats
+}
+
+type spanStats struct {
+	count  int
+	errors int
+	// durations is a ring buffer of the most recent span durations.
+	durations []time.Duration
+	next      int
+}
+
+type metricStats struct {
+	kind       string
+	dataPoints int
+}
+
+func newSummaryFormatter() *summaryFormatter {
+	return &summaryFormatter{
+		started: time.Now(),
+		spans:   make(map[string]map[string]*spanStats),
+		logs:    make(map[string]map[string]int),
+		metrics: make(map[string]map[string]*metricStats),
+	}
+}
+
+func (s *summaryFormatter) FormatTrace(req *collectortrace.ExportTraceServiceRequest) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		service := analyzer.ServiceName(rs.Resource)
+		if s.spans[service] == nil {
+			s.spans[service] = make(map[string]*spanStats)
+		}
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				stats := s.spans[service][span.Name]
+				if stats == nil {
+					stats = &spanStats{}
+					s.spans[service][span.Name] = stats
+				}
+				stats.add(span)
+			}
+		}
+	}
+	return ""
+}
+
+func (s *summaryFormatter) FormatMetric(req *collectormetrics.ExportMetricsServiceRequest) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rm := range req.ResourceMetrics {
+		service := analyzer.ServiceName(rm.Resource)
+		if s.metrics[service] == nil {
+			s.metrics[service] = make(map[string]*metricStats)
+		}
+		for _, sm := range rm.ScopeMetrics {
+			for _, metric := range sm.Metrics {
+				stats := s.metrics[service][metric.Name]
+				if stats == nil {
+					stats = &metricStats{}
+					s.metrics[service][metric.Name] = stats
+				}
+				stats.kind, stats.dataPoints = metricKind(metric), stats.dataPoints+dataPointCount(metric)
+			}
+		}
+	}
+	return ""
+}
+
+func (s *summaryFormatter) FormatLog(req *collectorlogs.ExportLogsServiceRequest) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rl := range req.ResourceLogs {
+		service := analyzer.ServiceName(rl.Resource)
+		if s.logs[service] == nil {
+			s.logs[service] = make(map[string]int)
+		}
+		for _, sl := range rl.ScopeLogs {
+			for _, record := range sl.LogRecords {
+				s.logs[service][severityName(record)]++
+			}
+		}
+	}
+	return ""
+}
+
+// refresh redraws the summary in place every interval, until the returned function is called. Stopping clears the
+// screen, so the final summary replaces the last refresh rather than repeating it.
+func (s *summaryFormatter) refresh(w io.Writer, interval time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fmt.Fprint(w, "\x1b[H\x1b[2J")
+				s.print(w)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		fmt.Fprint(w, "\x1b[H\x1b[2J")
+	}
+}
+
+func (s *summaryFormatter) print(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fmt.Fprintf(w, "📈 SUMMARY (%s, since %s)\n", time.Now().Format("15:04:05"), s.started.Format("15:04:05"))
+	s.printSpans(w)
+	s.printLogs(w)
+	s.printMetrics(w)
+	fmt.Fprintf(w, "└─────────────────────────────────────\n")
+}
+
+func (s *summaryFormatter) printSpans(w io.Writer) {
+	total, errors := 0, 0
+	for _, names := range s.spans {
+		for _, stats := range names {
+			total += stats.count
+			errors += stats.errors
+		}
+	}
+	fmt.Fprintf(w, "├─ Spans: %d (%d errors, %s)\n", total, errors, percent(errors, total))
+
+	services := sortedByCount(s.spans, func(names map[string]*spanStats) int {
+		count := 0
+		for _, stats := range names {
+			count += stats.count
+		}
+		return count
+	})
+	width := 0
+	for _, names := range s.spans {
+		width = max(width, maxWidth(slices.Collect(maps.Keys(names))))
+	}
+	for idx, service := range services {
+		connector, prefix := branch(idx, len(services), "│  ")
+		fmt.Fprintf(w, "│  %s %s\n", connector, serviceOrUnknown(service))
+
+		names := sortedByCount(s.spans[service], func(stats *spanStats) int { return stats.count })
+		for n, name := range names {
+			stats := s.spans[service][name]
+			nameConnector, _ := branch(n, len(names), "")
+			p50, p95, p99 := stats.percentiles()
+			fmt.Fprintf(w, "%s%s %s%s  %6d spans  %6s errors  p50 %-8s p95 %-8s p99 %s\n",
+				prefix, nameConnector, name, strings.Repeat(" ", width-utf8.RuneCountInString(name)),
+				stats.count, percent(stats.errors, stats.count), p50, p95, p99)
+		}
+	}
+}
+
+func (s *summaryFormatter) printLogs(w io.Writer) {
+	total := 0
+	for _, severities := range s.logs {
+		for _, count := range severities {
+			total += count
+		}
+	}
+	fmt.Fprintf(w, "├─ Logs: %d\n", total)
+
+	services := sortedByCount(s.logs, func(severities map[string]int) int {
+		count := 0
+		for _, n := range severities {
+			count += n
+		}
+		return count
+	})
+	for idx, service := range services {
+		connector, _ := branch(idx, len(services), "")
+		severities := slices.SortedFunc(maps.Keys(s.logs[service]), func(a, b string) int {
+			return cmp.Compare(severityRank(b), severityRank(a))
+		})
+		counts := make([]string, 0, len(severities))
+		for _, severity := range severities {
+			counts = append(counts, fmt.Sprintf("%s %d", severity, s.logs[service][severity]))
+		}
+		fmt.Fprintf(w, "│  %s %s: %s\n", connector, serviceOrUnknown(service), strings.Join(counts, ", "))
+	}
+}
+
+func (s *summaryFormatter) printMetrics(w io.Writer) {
+	names, dataPoints := 0, 0
+	for _, metrics := range s.metrics {
+		names += len(metrics)
+		for _, stats := range metrics {
+			dataPoints += stats.dataPoints
+		}
+	}
+	fmt.Fprintf(w, "├─ Metrics: %d names, %d data points\n", names, dataPoints)
+
+	services := sortedByCount(s.metrics, func(metrics map[string]*metricStats) int { return len(metrics) })
+	width := 0
+	for _, metrics := range s.metrics {
+		width = max(width, maxWidth(slices.Collect(maps.Keys(metrics))))
+	}
+	for idx, service := range services {
+		connector, prefix := branch(idx, len(services), "│  ")
+		fmt.Fprintf(w, "│  %s %s\n", connector, serviceOrUnknown(service))
+
+		metrics := slices.Sorted(maps.Keys(s.metrics[service]))
+		for n, name := range metrics {
+			stats := s.metrics[service][name]
+			nameConnector, _ := branch(n, len(metrics), "")
+			fmt.Fprintf(w, "%s%s %s%s  %6d data points  %s\n",
+				prefix, nameConnector, name, strings.Repeat(" ", width-utf8.RuneCountInString(name)), stats.dataPoints, stats.kind)
+		}
+	}
+}
+
+func (stats *spanStats) add(span *prototrace.Span) {
+	stats.count++
+	if span.Status.GetCode() == prototrace.Status_STATUS_CODE_ERROR {
+		stats.errors++
+	}
+
+	duration := time.Duration(int64(span.EndTimeUnixNano) - int64(span.StartTimeUnixNano))
+	if len(stats.durations) < maxSummaryDurations {
+		stats.durations = append(stats.durations, duration)
+	} else {
+		stats.durations[stats.next] = duration
+		stats.next = (stats.next + 1) % maxSummaryDurations
+	}
+}
+
+// percentiles returns the nearest-rank p50, p95 and p99 of the recent durations.
+func (stats *spanStats) percentiles() (string, string, string) {
+	sorted := slices.Sorted(slices.Values(stats.durations))
+	at := func(p float64) string {
+		if len(sorted) == 0 {
+			return "-"
+		}
+		idx := min(int(float64(len(sorted))*p+0.999999)-1, len(sorted)-1)
+		return roundDuration(sorted[max(idx, 0)])
+	}
+	return at(0.50), at(0.95), at(0.99)
+}
+
+// roundDuration rounds to the microsecond, or to the millisecond for durations over a second, e.g. 1.234ms rather than 1.234567ms.
+func roundDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	default:
+		return d.String()
+	}
+}
+
+// severityName groups log records by the short name of their severity number's range, falling back to the
+// severity text when the number is unset.
+func severityName(record *protologs.LogRecord) string {
+	number := record.SeverityNumber
+	switch {
+	case number >= protologs.SeverityNumber_SEVERITY_NUMBER_FATAL:
+		return "FATAL"
+	case number >= protologs.SeverityNumber_SEVERITY_NUMBER_ERROR:
+		return "ERROR"
+	case number >= protologs.SeverityNumber_SEVERITY_NUMBER_WARN:
+		return "WARN"
+	case number >= protologs.SeverityNumber_SEVERITY_NUMBER_INFO:
+		return "INFO"
+	case number >= protologs.SeverityNumber_SEVERITY_NUMBER_DEBUG:
+		return "DEBUG"
+	case number > protologs.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED:
+		return "TRACE"
+	case record.SeverityText != "":
+		return strings.ToUpper(record.SeverityText)
+	default:
+		return "UNSPECIFIED"
+	}
+}
+
+func severityRank(name string) int {
+	return slices.Index([]string{"UNSPECIFIED", "TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}, name)
+}
+
+func metricKind(metric *protometrics.Metric) string {
+	switch metric.Data.(type) {
+	case *protometrics.Metric_Gauge:
+		return "Gauge"
+	case *protometrics.Metric_Sum:
+		return "Sum"
+	case *protometrics.Metric_Histogram:
+		return "Histogram"
+	case *protometrics.Metric_ExponentialHistogram:
+		return "ExponentialHistogram"
+	case *protometrics.Metric_Summary:
+		return "Summary"
+	default:
+		return "<no data>"
+	}
+}
+
+func dataPointCount(metric *protometrics.Metric) int {
+	switch data := metric.Data.(type) {
+	case *protometrics.Metric_Gauge:
+		return len(data.Gauge.DataPoints)
+	case *protometrics.Metric_Sum:
+		return len(data.Sum.DataPoints)
+	case *protometrics.Metric_Histogram:
+		return len(data.Histogram.DataPoints)
+	case *protometrics.Metric_ExponentialHistogram:
+		return len(data.ExponentialHistogram.DataPoints)
+	case *protometrics.Metric_Summary:
+		return len(data.Summary.DataPoints)
+	default:
+		return 0
+	}
+}
+
+// sortedByCount returns the keys of m, highest count first, then by name.
+func sortedByCount[V any](m map[string]V, count func(V) int) []string {
+	return slices.SortedFunc(maps.Keys(m), func(a, b string) int {
+		return cmp.Or(cmp.Compare(count(m[b]), count(m[a])), cmp.Compare(a, b))
+	})
+}
+
+// branch returns the tree connector for item idx of n, and the prefix for that item's children.
+func branch(idx, n int, prefix string) (string, string) {
+	if idx == n-1 {
+		return "└─", prefix + "   "
+	}
+	return "├─", prefix + "│  "
+}
+
+func maxWidth(names []string) int {
+	width := 0
+	for _, name := range names {
+		width = max(width, utf8.RuneCountInString(name))
+	}
+	return width
+}
+
+func percent(n, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)/float64(total)*100)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/jimschubert/otel-relay/internal/formatter"
+	"golang.org/x/term"
+)
+
+// stdout is shared by the event loop and the goroutines that print assembled traces and stats. Each print is a
+// single Write, so serializing writes keeps their output from interleaving.
+var stdout io.Writer = &lockedWriter{w: os.Stdout}
+
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// terminalOptions enables color and width-aware truncation for the tree output, based on --color and whether
+// stdout is a terminal.
+func terminalOptions() []formatter.TreeOption {
+	fd := int(os.Stdout.Fd())
+
+	opts := []formatter.TreeOption{formatter.WithColor(useColor())}
+	if term.IsTerminal(fd) {
+		if width, _, err := term.GetSize(fd); err == nil {
+			opts = append(opts, formatter.WithWidth(width))
+		}
+	}
+	return opts
+}
+
+// useColor applies --color: auto enables color only when stdout is a terminal and NO_COLOR (https://no-color.org)
+// is unset.
+func useColor() bool {
+	switch CLI.Color {
+	case "always":
+		return true
+	case "auto":
+		return term.IsTerminal(int(os.Stdout.Fd())) && os.Getenv("NO_COLOR") == ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jimschubert/otel-relay/internal/assembler"
+	"github.com/jimschubert/otel-relay/internal/formatter"
+	"github.com/jimschubert/otel-relay/proto/inspector"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// runTrace looks up one trace in the daemon's history and prints its spans as a waterfall, with its logs nested under
+// their spans. With --format jaeger or zipkin, it writes the trace's spans as JSON instead.
+func runTrace(w io.Writer, id string) error {
+	traceID, err := hex.DecodeString(id)
+	if err != nil || len(traceID) != 16 {
+		return fmt.Errorf("invalid trace ID %q: expected 32 hex characters", id)
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := inspector.NewInspectorServiceClient(conn).GetTrace(ctx, &inspector.TraceRequest{TraceId: traceID})
+	if err != nil {
+		return fmt.Errorf("failed to look up trace: %w", err)
+	}
+	if len(resp.Events) == 0 {
+		return fmt.Errorf("trace %s not found in the last %d retained exports", id, resp.History)
+	}
+
+	if file := newTraceFileFormatter(); file != nil {
+		for _, event := range resp.Events {
+			if event.Type != inspector.TelemetryType_TELEMETRY_TYPE_TRACE {
+				continue
+			}
+			var req collectortrace.ExportTraceServiceRequest
+			if err := proto.Unmarshal(event.Data, &req); err != nil {
+				return fmt.Errorf("failed to unmarshal trace: %w", err)
+			}
+			file.FormatTrace(&req)
+		}
+		return file.Flush(w)
+	}
+
+	tree := formatter.NewTreeFormatter(CLI.Verbose, treeOptions()...)
+
+	var trace *assembler.Trace
+	traces := assembler.New(time.Hour, func(t *assembler.Trace) { trace = t })
+	logs := make([]*collectorlogs.ExportLogsServiceRequest, 0)
+	for _, event := range resp.Events {
+		switch event.Type {
+		case inspector.TelemetryType_TELEMETRY_TYPE_TRACE:
+			var req collectortrace.ExportTraceServiceRequest
+			if err := proto.Unmarshal(event.Data, &req); err != nil {
+				return fmt.Errorf("failed to unmarshal trace: %w", err)
+			}
+			tree.ObserveTrace(&req)
+			traces.Add(&req)
+		case inspector.TelemetryType_TELEMETRY_TYPE_LOG:
+			var req collectorlogs.ExportLogsServiceRequest
+			if err := proto.Unmarshal(event.Data, &req); err != nil {
+				return fmt.Errorf("failed to unmarshal log: %w", err)
+			}
+			traces.AddLogs(&req)
+			logs = append(logs, &req)
+		}
+	}
+	traces.Close()
+
+	// the waterfall already nests the trace's logs under their spans, so they're only printed on their own when
+	// there's no waterfall to nest them in
+	if trace != nil {
+		fmt.Fprint(w, tree.FormatWaterfall(trace))
+		return nil
+	}
+	fmt.Fprintf(w, "\nNo spans retained for trace %s, only logs\n", id)
+	for _, req := range logs {
+		fmt.Fprint(w, tree.FormatLog(req))
+	}
+	return nil
+}
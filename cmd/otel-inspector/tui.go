@@ -0,0 +1,613 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/eiannone/keyboard"
+	"github.com/jimschubert/otel-relay/internal/formatter"
+	"github.com/jimschubert/otel-relay/proto/inspector"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+	"golang.org/x/term"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxTUIItems bounds how much history the TUI keeps. The oldest items are dropped first.
+	maxTUIItems = 10000
+	tuiRefresh  = 50 * time.Millisecond
+
+	ansiReverse = "\x1b[7m"
+	ansiReset   = "\x1b[0m"
+)
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+var tuiTabs = []struct {
+	name   string
+	signal inspector.TelemetryType
+}{
+	{"All", inspector.TelemetryType_TELEMETRY_TYPE_UNSPECIFIED},
+	{"Traces", inspector.TelemetryType_TELEMETRY_TYPE_TRACE},
+	{"Logs", inspector.TelemetryType_TELEMETRY_TYPE_LOG},
+	{"Metrics", inspector.TelemetryType_TELEMETRY_TYPE_METRIC},
+}
+
+// tuiItem is a single span, log record or metric, cut out of the request it arrived in along with its resource and
+// scope, so the formatters can render it on its own.
+type tuiItem struct {
+	signal     inspector.TelemetryType
+	line       string
+	violations []*inspector.Violation
+	trace      *collectortrace.ExportTraceServiceRequest
+	metric     *collectormetrics.ExportMetricsServiceRequest
+	log        *collectorlogs.ExportLogsServiceRequest
+	// text is the lowercased verbose rendering that searches match against, built on first use.
+	text string
+}
+
+func (i *tuiItem) render(form formatter.Formatter) string {
+	switch {
+	case i.trace != nil:
+		return form.FormatTrace(i.trace)
+	case i.metric != nil:
+		return form.FormatMetric(i.metric)
+	case i.log != nil:
+		return form.FormatLog(i.log)
+	default:
+		return ""
+	}
+}
+
+// tui is the state of the full-screen terminal UI: a list of received items filtered by signal and search, the
+// selected item's details, and whether the live stream is paused.
+type tui struct {
+	mu      sync.Mutex
+	color   bool
+	compact *formatter.CompactFormatter
+	plain   *formatter.TreeFormatter
+
+	items   []*tuiItem
+	pending []*tuiItem
+	visible []*tuiItem
+
+	selected int
+	follow   bool
+	paused   bool
+	tab      int
+	query    string
+	input    []rune
+	typing   bool
+	verbose  bool
+	status   string
+	dirty    bool
+
+	listTop      int
+	listHeight   int
+	detailTop    int
+	detailHeight int
+
+	detail        []string
+	detailItem    *tuiItem
+	detailWidth   int
+	detailVerbose bool
+}
+
+func newTUI() *tui {
+	return &tui{
+		color:   useColor(),
+		compact: formatter.NewCompactFormatter([]string{}, false),
+		plain:   formatter.NewTreeFormatter(true),
+		follow:  true,
+		verbose: CLI.Verbose,
+		dirty:   true,
+	}
+}
+
+// runTUI streams signals into a full-screen terminal UI until the user quits or the stream ends.
+func runTUI() error {
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("the tui needs stdout to be a terminal")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn, err := connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := inspector.NewInspectorServiceClient(conn).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create stream: %w", err)
+	}
+
+	keys, err := keyboard.GetKeys(16)
+	if err != nil {
+		return fmt.Errorf("failed to read keyboard input: %w", err)
+	}
+	defer keyboard.Close()
+
+	ui := newTUI()
+
+	// use the alternate screen, so the terminal's scrollback is restored on exit, and route log output to the footer
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+	log.SetOutput(ui)
+	defer log.SetOutput(os.Stderr)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+			ui.add(ui.newItems(event))
+		}
+	}()
+
+	ticker := time.NewTicker(tuiRefresh)
+	defer ticker.Stop()
+
+	width, height := 0, 0
+	for {
+		select {
+		case <-sigChan:
+			return nil
+		case err := <-errs:
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error receiving event: %w", err)
+		case event := <-keys:
+			if event.Err == nil && ui.handleKey(event.Rune, event.Key) {
+				return nil
+			}
+		case <-ticker.C:
+			w, h, err := term.GetSize(fd)
+			if err != nil {
+				continue
+			}
+			if w != width || h != height {
+				width, height = w, h
+				ui.markDirty()
+			}
+			if screen, ok := ui.draw(width, height); ok {
+				fmt.Print(screen)
+			}
+		}
+	}
+}
+
+// newItems splits an event into an item per span, log record and metric.
+func (t *tui) newItems(event *inspector.TelemetryEvent) []*tuiItem {
+	items := make([]*tuiItem, 0)
+	switch event.Type {
+	case inspector.TelemetryType_TELEMETRY_TYPE_TRACE:
+		var req collectortrace.ExportTraceServiceRequest
+		if err := proto.Unmarshal(event.Data, &req); err != nil {
+			log.Printf("Error unmarshaling trace: %v", err)
+			return nil
+		}
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					items = append(items, &tuiItem{trace: &collectortrace.ExportTraceServiceRequest{
+						ResourceSpans: []*prototrace.ResourceSpans{{
+							Resource:   rs.Resource,
+							SchemaUrl:  rs.SchemaUrl,
+							ScopeSpans: []*prototrace.ScopeSpans{{Scope: ss.Scope, SchemaUrl: ss.SchemaUrl, Spans: []*prototrace.Span{span}}},
+						}},
+					}})
+				}
+			}
+		}
+
+	case inspector.TelemetryType_TELEMETRY_TYPE_METRIC:
+		var req collectormetrics.ExportMetricsServiceRequest
+		if err := proto.Unmarshal(event.Data, &req); err != nil {
+			log.Printf("Error unmarshaling metric: %v", err)
+			return nil
+		}
+		for _, rm := range req.ResourceMetrics {
+			for _, sm := range rm.ScopeMetrics {
+				for _, metric := range sm.Metrics {
+					items = append(items, &tuiItem{metric: &collectormetrics.ExportMetricsServiceRequest{
+						ResourceMetrics: []*protometrics.ResourceMetrics{{
+							Resource:     rm.Resource,
+							SchemaUrl:    rm.SchemaUrl,
+							ScopeMetrics: []*protometrics.ScopeMetrics{{Scope: sm.Scope, SchemaUrl: sm.SchemaUrl, Metrics: []*protometrics.Metric{metric}}},
+						}},
+					}})
+				}
+			}
+		}
+
+	case inspector.TelemetryType_TELEMETRY_TYPE_LOG:
+		var req collectorlogs.ExportLogsServiceRequest
+		if err := proto.Unmarshal(event.Data, &req); err != nil {
+			log.Printf("Error unmarshaling log: %v", err)
+			return nil
+		}
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				for _, record := range sl.LogRecords {
+					items = append(items, &tuiItem{log: &collectorlogs.ExportLogsServiceRequest{
+						ResourceLogs: []*protologs.ResourceLogs{{
+							Resource:  rl.Resource,
+							SchemaUrl: rl.SchemaUrl,
+							ScopeLogs: []*protologs.ScopeLogs{{Scope: sl.Scope, SchemaUrl: sl.SchemaUrl, LogRecords: []*protologs.LogRecord{record}}},
+						}},
+					}})
+				}
+			}
+		}
+	}
+
+	for _, item := range items {
+		item.signal = event.Type
+		item.violations = event.Violations
+
+		// a metric has a compact line per data point; the list shows the first
+		lines := strings.Split(strings.TrimRight(item.render(t.compact), "\n"), "\n")
+		item.line = lines[0]
+		if len(lines) > 1 {
+			item.line += fmt.Sprintf("  (+%d data points)", len(lines)-1)
+		}
+		if len(item.violations) > 0 {
+			item.line += fmt.Sprintf("  (%d OTLP violations)", len(item.violations))
+		}
+	}
+	return items
+}
+
+// add appends newly received items, or holds them back while paused.
+func (t *tui) add(items []*tuiItem) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dirty = true
+	if t.paused {
+		t.pending = append(t.pending, items...)
+		if len(t.pending) > maxTUIItems {
+			t.pending = slices.Clone(t.pending[len(t.pending)-maxTUIItems:])
+		}
+		return
+	}
+	t.append(items)
+}
+
+func (t *tui) append(items []*tuiItem) {
+	t.items = append(t.items, items...)
+	// trim in batches, since trimming refilters every item
+	if len(t.items) > maxTUIItems+maxTUIItems/10 {
+		t.items = slices.Clone(t.items[len(t.items)-maxTUIItems:])
+		t.refilter()
+	} else {
+		for _, item := range items {
+			if t.matches(item) {
+				t.visible = append(t.visible, item)
+			}
+		}
+	}
+	if t.follow {
+		t.selected = max(len(t.visible)-1, 0)
+	}
+}
+
+// refilter rebuilds the visible items for the current tab and search, keeping the selected item if it's still visible.
+func (t *tui) refilter() {
+	var current *tuiItem
+	if t.selected < len(t.visible) {
+		current = t.visible[t.selected]
+	}
+
+	t.visible = make([]*tuiItem, 0, len(t.items))
+	for _, item := range t.items {
+		if t.matches(item) {
+			t.visible = append(t.visible, item)
+		}
+	}
+
+	t.selected = max(len(t.visible)-1, 0)
+	if idx := slices.Index(t.visible, current); idx >= 0 {
+		t.selected = idx
+	}
+	t.follow = t.selected >= len(t.visible)-1
+	t.dirty = true
+}
+
+func (t *tui) matches(item *tuiItem) bool {
+	if signal := tuiTabs[t.tab].signal; signal != inspector.TelemetryType_TELEMETRY_TYPE_UNSPECIFIED && item.signal != signal {
+		return false
+	}
+	if t.query == "" {
+		return true
+	}
+	if item.text == "" {
+		item.text = strings.ToLower(item.render(t.plain))
+	}
+	return strings.Contains(item.text, strings.ToLower(t.query))
+}
+
+// handleKey applies a key press, returning true when the user quits.
+func (t *tui) handleKey(char rune, key keyboard.Key) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dirty = true
+	t.status = ""
+
+	if t.typing {
+		switch key {
+		case keyboard.KeyEnter:
+			t.typing = false
+			t.query = string(t.input)
+			t.refilter()
+		case keyboard.KeyEsc:
+			t.typing = false
+			t.input = []rune(t.query)
+		case keyboard.KeyBackspace, keyboard.KeyBackspace2:
+			if len(t.input) > 0 {
+				t.input = t.input[:len(t.input)-1]
+			}
+		case keyboard.KeySpace:
+			t.input = append(t.input, ' ')
+		case keyboard.KeyCtrlC:
+			return true
+		default:
+			if char != 0 {
+				t.input = append(t.input, char)
+			}
+		}
+		return false
+	}
+
+	switch {
+	case key == keyboard.KeyCtrlC || char == 'q':
+		return true
+	case key == keyboard.KeyArrowUp || char == 'k':
+		t.move(-1)
+	case key == keyboard.KeyArrowDown || char == 'j':
+		t.move(1)
+	case key == keyboard.KeyPgup:
+		t.move(-t.listHeight)
+	case key == keyboard.KeyPgdn:
+		t.move(t.listHeight)
+	case key == keyboard.KeyHome || char == 'g':
+		t.move(-len(t.visible))
+	case key == keyboard.KeyEnd || char == 'G':
+		t.move(len(t.visible))
+	case char == 'u':
+		t.detailTop = max(t.detailTop-t.detailHeight/2, 0)
+	case char == 'd':
+		t.detailTop += t.detailHeight / 2
+	case key == keyboard.KeyTab:
+		t.tab = (t.tab + 1) % len(tuiTabs)
+		t.refilter()
+	case char >= '1' && int(char-'1') < len(tuiTabs):
+		t.tab = int(char - '1')
+		t.refilter()
+	case key == keyboard.KeySpace || char == 'p':
+		t.paused = !t.paused
+		if !t.paused {
+			t.append(t.pending)
+			t.pending = nil
+		}
+	case char == '/':
+		t.typing = true
+		t.input = []rune(t.query)
+	case key == keyboard.KeyEsc:
+		t.query, t.input = "", nil
+		t.refilter()
+	case char == 'v':
+		t.verbose = !t.verbose
+	}
+	return false
+}
+
+// move changes the selection by delta items. Selecting the newest item follows new items as they arrive.
+func (t *tui) move(delta int) {
+	t.selected = min(max(t.selected+delta, 0), max(len(t.visible)-1, 0))
+	t.follow = t.selected >= len(t.visible)-1
+	t.detailTop = 0
+}
+
+func (t *tui) markDirty() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dirty = true
+}
+
+// Write shows log output in the footer, since printing it would corrupt the screen.
+func (t *tui) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lines := strings.Split(strings.TrimSpace(string(p)), "\n")
+	t.status = lines[len(lines)-1]
+	t.dirty = true
+	return len(p), nil
+}
+
+// draw renders the whole screen: signal tabs, the item list, the selected item's details and a footer.
+// It returns false when nothing has changed since the last draw.
+func (t *tui) draw(width, height int) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.dirty || width <= 0 || height < 5 {
+		return "", false
+	}
+	t.dirty = false
+
+	rows := make([]string, 0, height)
+	rows = append(rows, t.header(width))
+
+	t.listHeight = max((height-3)*2/5, 1)
+	if t.selected < t.listTop {
+		t.listTop = t.selected
+	}
+	if t.selected >= t.listTop+t.listHeight {
+		t.listTop = t.selected - t.listHeight + 1
+	}
+	t.listTop = max(min(t.listTop, len(t.visible)-t.listHeight), 0)
+	for row := range t.listHeight {
+		idx := t.listTop + row
+		switch {
+		case idx >= len(t.visible):
+			rows = append(rows, "")
+		case idx == t.selected:
+			rows = append(rows, ansiReverse+padLine(clip(t.visible[idx].line, width), width)+ansiReset)
+		default:
+			rows = append(rows, clip(t.visible[idx].line, width))
+		}
+	}
+
+	rows = append(rows, clip("── Details "+strings.Repeat("─", width), width))
+
+	t.detailHeight = max(height-len(rows)-1, 0)
+	detail := t.detailLines(width)
+	t.detailTop = max(min(t.detailTop, len(detail)-t.detailHeight), 0)
+	for row := range t.detailHeight {
+		if idx := t.detailTop + row; idx < len(detail) {
+			rows = append(rows, clip(detail[idx], width))
+		} else {
+			rows = append(rows, "")
+		}
+	}
+	rows = append(rows, t.footer(width))
+
+	var buf strings.Builder
+	buf.WriteString("\x1b[H")
+	for idx, row := range rows {
+		buf.WriteString("\x1b[2K")
+		buf.WriteString(row)
+		if idx < len(rows)-1 {
+			buf.WriteString("\r\n")
+		}
+	}
+	return buf.String(), true
+}
+
+func (t *tui) header(width int) string {
+	counts := make(map[inspector.TelemetryType]int)
+	for _, item := range t.items {
+		counts[item.signal]++
+	}
+
+	var buf strings.Builder
+	for idx, tab := range tuiTabs {
+		count := len(t.items)
+		if tab.signal != inspector.TelemetryType_TELEMETRY_TYPE_UNSPECIFIED {
+			count = counts[tab.signal]
+		}
+		label := fmt.Sprintf(" %d %s (%d) ", idx+1, tab.name, count)
+		if idx == t.tab {
+			label = ansiReverse + label + ansiReset
+		}
+		buf.WriteString(label)
+	}
+
+	state := "● LIVE"
+	if t.paused {
+		state = fmt.Sprintf("⏸ PAUSED (%d new)", len(t.pending))
+	}
+	if t.query != "" {
+		state = fmt.Sprintf("search: %q  %s", t.query, state)
+	}
+	gap := max(width-visibleLen(buf.String())-utf8.RuneCountInString(state)-1, 1)
+	return clip(buf.String()+strings.Repeat(" ", gap)+state, width)
+}
+
+func (t *tui) footer(width int) string {
+	switch {
+	case t.typing:
+		return clip("/"+string(t.input)+"█", width)
+	case t.status != "":
+		return clip(t.status, width)
+	default:
+		return clip("↑↓ select  tab/1-4 signal  / search  esc clear  space pause  d/u scroll details  v verbose  q quit", width)
+	}
+}
+
+// detailLines renders the selected item with the tree formatter, caching the result until the selection, width or
+// verbosity changes.
+func (t *tui) detailLines(width int) []string {
+	if len(t.visible) == 0 {
+		t.detailItem = nil
+		return []string{"Waiting for signals..."}
+	}
+
+	item := t.visible[t.selected]
+	if item != t.detailItem || width != t.detailWidth || t.verbose != t.detailVerbose {
+		form := formatter.NewTreeFormatter(t.verbose, formatter.WithColor(t.color), formatter.WithWidth(width))
+		text := formatViolations(item.violations) + item.render(form)
+		t.detail = strings.Split(strings.Trim(text, "\n"), "\n")
+		t.detailItem, t.detailWidth, t.detailVerbose = item, width, t.verbose
+	}
+	return t.detail
+}
+
+// clip cuts s to width visible characters, skipping over ANSI escape sequences so colored lines aren't broken.
+func clip(s string, width int) string {
+	var buf strings.Builder
+	visible, escaped := 0, false
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			end := i + 1
+			for end < len(s) && (s[end] < '@' || s[end] > '~' || end == i+1) {
+				end++
+			}
+			end = min(end+1, len(s))
+			buf.WriteString(s[i:end])
+			escaped = true
+			i = end
+			continue
+		}
+		if visible >= width {
+			break
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		buf.WriteRune(r)
+		visible++
+		i += size
+	}
+	if escaped {
+		buf.WriteString(ansiReset)
+	}
+	return buf.String()
+}
+
+// visibleLen counts the characters of s that take up space on screen, ignoring ANSI escape sequences.
+func visibleLen(s string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(s, ""))
+}
+
+func padLine(s string, width int) string {
+	if n := visibleLen(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
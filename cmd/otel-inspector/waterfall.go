@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jimschubert/otel-relay/internal/assembler"
+	"github.com/jimschubert/otel-relay/internal/formatter"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+var (
+	_ formatter.Formatter = (*waterfallFormatter)(nil)
+)
+
+// waterfallFormatter buffers traces in an assembler and prints each as a waterfall to out once its window has elapsed.
+// Metrics and logs are printed as they arrive, and logs are also shown under their span in the waterfall.
+type waterfallFormatter struct {
+	*formatter.TreeFormatter
+	assembler *assembler.Assembler
+}
+
+// newWaterfallFormatter prints from the assembler's goroutine, so out must be safe to share with the event loop.
+func newWaterfallFormatter(out io.Writer, tree *formatter.TreeFormatter, window time.Duration) *waterfallFormatter {
+	return &waterfallFormatter{
+		TreeFormatter: tree,
+		assembler: assembler.New(window, func(trace *assembler.Trace) {
+			fmt.Fprint(out, tree.FormatWaterfall(trace))
+		}),
+	}
+}
+
+func (w *waterfallFormatter) FormatTrace(req *collectortrace.ExportTraceServiceRequest) string {
+	w.TreeFormatter.ObserveTrace(req)
+	w.assembler.Add(req)
+	return ""
+}
+
+// FormatLog prints logs as they arrive, and also nests those with a trace ID under their span in the waterfall.
+func (w *waterfallFormatter) FormatLog(req *collectorlogs.ExportLogsServiceRequest) string {
+	w.assembler.AddLogs(req)
+	return w.TreeFormatter.FormatLog(req)
+}
+
+// Close prints the traces still being assembled.
+func (w *waterfallFormatter) Close() {
+	w.assembler.Close()
+}
@@ -6,19 +6,25 @@ import (
 	"os"
 	"os/signal"
 	"slices"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/jimschubert/otel-relay/inspector"
 	"github.com/jimschubert/otel-relay/internal/emitter"
 	"github.com/jimschubert/otel-relay/internal/grpcserver"
 	"github.com/jimschubert/otel-relay/internal/observe"
-	"github.com/jimschubert/otel-relay/internal/proxy"
+	"github.com/jimschubert/otel-relay/proxy"
+	"github.com/jimschubert/otel-relay/snapshot"
 )
 
 const (
-	grpc = "gRPC"
-	http = "HTTP"
+	grpc   = "gRPC"
+	http   = "HTTP"
+	zipkin = "Zipkin"
+	prom   = "Prometheus"
 )
 
 var (
@@ -32,12 +38,32 @@ var CLI struct {
 	Upstream            string           `short:"u" optional:"" placeholder:"<host:port>" help:"Upstream OTLP collector address (optional, e.g. 'localhost:4317')"`
 	ListenHttp          string           `short:"L" optional:"" placeholder:"<port>" help:"Address to listen on for HTTP/JSON, e.g. ':14318' (optional)"`
 	UpstreamHttp        string           `short:"U" optional:"" placeholder:"<scheme:host:port>" help:"Upstream HTTP collector URL (optional, e.g. 'http://localhost:4318')"`
+	ListenZipkin        string           `optional:"" placeholder:"<addr>" help:"Address to listen on for Zipkin v2 JSON spans, e.g. ':9411' (optional)"`
+	UpstreamZipkin      string           `optional:"" placeholder:"<scheme:host:port>" help:"Upstream URL for Zipkin spans (optional, e.g. 'http://localhost:9411')"`
+	ZipkinAsOtlp        bool             `name:"zipkin-as-otlp" help:"Forward Zipkin spans to --upstream-zipkin as OTLP/HTTP (e.g. 'http://localhost:4318') instead of as Zipkin"`
+	ListenPrometheus    string           `optional:"" placeholder:"<addr>" help:"Address to listen on for Prometheus remote write, e.g. ':19090' (optional)"`
+	UpstreamPrometheus  string           `optional:"" placeholder:"<url>" help:"Upstream remote write URL (optional, e.g. 'http://localhost:9090/api/v1/write')"`
 	Socket              string           `short:"s" default:"/tmp/otel-relay.sock" optional:"" help:"Path to Unix domain socket for gRPC inspector service (optional)"`
 	Emit                bool             `negatable:"" default:"true"  help:"Whether to emit signals to unix socket"`
 	RelayMetrics        bool             `default:"true" help:"Whether to emit this tooling's own metrics (default: true)"`
 	RelayMetricsBackend string           `optional:"" default:"" help:"OTLP endpoint to push metrics to (default: same as --upstream/-u if set, otherwise localhost:4317)"`
+	Validate            bool             `help:"Check signals against the OTLP specification and flag violations in the inspector"`
+	RejectInvalid       bool             `help:"Like --validate, but also reject invalid signals (gRPC INVALID_ARGUMENT, HTTP 400) instead of forwarding them"`
+	Web                 string           `optional:"" placeholder:"<addr>" help:"Serve a web UI for the inspector socket's signals on this address, e.g. ':8080' (optional)"`
+	History             int              `default:"${history}" help:"Number of trace and log exports, up to 64 MiB in total, the inspector socket keeps for 'otel-inspector trace' lookups (0 disables)"`
 	Daemon              string           `optional:"" hidden:"" help:"Internal: run as daemon (socket path)"`
 	Version             kong.VersionFlag `short:"v" help:"Print version information"`
+
+	Serve    struct{}    `cmd:"" default:"1" hidden:"" help:"Run the relay (default)"`
+	Snapshot snapshotCmd `cmd:"" help:"Run the relay, then compare the captured telemetry against a golden file"`
+}
+
+type snapshotCmd struct {
+	Golden                  string        `arg:"" help:"Path to the golden file"`
+	Update                  bool          `help:"Write the golden file instead of comparing against it"`
+	Duration                time.Duration `optional:"" help:"Stop capturing after this long (default: until Ctrl+C)"`
+	IgnoreResourceAttribute []string      `optional:"" help:"Additional resource attributes to strip; a trailing '.' strips all keys with that prefix"`
+	MetricValues            bool          `help:"Compare metric data point values, not only metric definitions and attribute sets"`
 }
 
 func main() {
@@ -49,18 +75,55 @@ func main() {
 		kong.UsageOnError(),
 		kong.Vars{
 			"version": formattedVersion,
+			"history": strconv.Itoa(grpcserver.DefaultHistory),
 		},
 	)
 
-	if err := run(); err != nil {
+	var err error
+	if strings.HasPrefix(ctx.Command(), "snapshot") {
+		err = runSnapshot()
+	} else {
+		err = run(nil, nil)
+	}
+	if err != nil {
 		ctx.Errorf("Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
+func runSnapshot() error {
+	var done <-chan time.Time
+	if CLI.Snapshot.Duration > 0 {
+		done = time.After(CLI.Snapshot.Duration)
+	}
+
+	recorder := snapshot.NewRecorder()
+	if err := run(recorder, done); err != nil {
+		return err
+	}
+
+	opts := []snapshot.Option{snapshot.WithIgnoredResourceAttributes(CLI.Snapshot.IgnoreResourceAttribute...)}
+	if CLI.Snapshot.MetricValues {
+		opts = append(opts, snapshot.WithMetricValues())
+	}
+
+	if err := snapshot.Compare(CLI.Snapshot.Golden, recorder.Snapshot(), CLI.Snapshot.Update, opts...); err != nil {
+		return err
+	}
+
+	if CLI.Snapshot.Update {
+		log.Printf("Wrote golden file %s", CLI.Snapshot.Golden)
+	} else {
+		log.Printf("Captured telemetry matches golden file %s", CLI.Snapshot.Golden)
+	}
+	return nil
+}
+
+// run starts the relay and blocks until interrupted, a proxy fails, or done fires.
+// Every inspected request is also sent to extra, when set.
+func run(extra inspector.Emitter, done <-chan time.Time) error {
 	if CLI.Daemon != "" {
-		grpcserver.RunDaemon(CLI.Daemon)
+		grpcserver.RunDaemon(CLI.Daemon, grpcserver.WithWeb(CLI.Web), grpcserver.WithHistory(CLI.History))
 		return nil
 	}
 
@@ -88,25 +151,68 @@ func run() error {
 		fmt.Printf("%sListening (%s): disabled\n", prefix, http)
 	}
 
+	if CLI.ListenZipkin != "" {
+		fmt.Printf("%sListening (%s): %s\n", prefix, zipkin, CLI.ListenZipkin)
+		switch {
+		case CLI.UpstreamZipkin == "":
+			fmt.Printf("%sForwarding (%s): disabled (inspection only)\n", prefix, zipkin)
+		case CLI.ZipkinAsOtlp:
+			fmt.Printf("%sForwarding (%s) as OTLP to: %s\n", prefix, zipkin, CLI.UpstreamZipkin)
+		default:
+			fmt.Printf("%sForwarding (%s) to: %s\n", prefix, zipkin, CLI.UpstreamZipkin)
+		}
+	}
+
+	if CLI.ListenPrometheus != "" {
+		fmt.Printf("%sListening (%s): %s\n", prefix, prom, CLI.ListenPrometheus)
+		if CLI.UpstreamPrometheus != "" {
+			fmt.Printf("%sForwarding (%s) to: %s\n", prefix, prom, CLI.UpstreamPrometheus)
+		} else {
+			fmt.Printf("%sForwarding (%s): disabled (inspection only)\n", prefix, prom)
+		}
+	}
+
 	if CLI.Emit {
 		fmt.Printf("%sInspector socket (gRPC): %s\n", prefix, CLI.Socket)
+		if CLI.Web != "" {
+			fmt.Printf("%sWeb UI: %s\n", prefix, CLI.Web)
+		}
 	} else {
 		fmt.Printf("%sInspector socket: disabled\n", prefix)
 	}
 
+	switch {
+	case CLI.RejectInvalid:
+		fmt.Printf("%sOTLP validation: rejecting invalid signals\n", prefix)
+	case CLI.Validate:
+		fmt.Printf("%sOTLP validation: flagging invalid signals\n", prefix)
+	}
+
 	log.Printf("OTel Relay is running. Press Ctrl+C to stop. (PID: %d)\n", os.Getpid())
 
-	var emit emitter.Emitter
+	var emit inspector.Emitter
 	if CLI.Emit {
-		if err := grpcserver.EnsureServerRunning(CLI.Socket); err != nil {
+		daemonArgs := []string{"--history", strconv.Itoa(CLI.History)}
+		if CLI.Web != "" {
+			daemonArgs = append(daemonArgs, "--web", CLI.Web)
+		}
+		started, err := grpcserver.EnsureServerRunning(CLI.Socket, daemonArgs...)
+		if err != nil {
 			return fmt.Errorf("failed to ensure gRPC server is running: %w", err)
 		}
+		if !started && (CLI.Web != "" || CLI.History != grpcserver.DefaultHistory) {
+			log.Printf("Warning: --web and --history only apply when the relay starts the daemon, and one is already running on %s", CLI.Socket)
+		}
 		emit = emitter.NewGrpcEmitter(CLI.Socket)
 	} else {
-		emit = emitter.NewNoopEmitter()
+		emit = inspector.NewNoopEmitter()
 	}
 
-	var metrics *observe.Metrics
+	if extra != nil {
+		emit = inspector.MultiEmitter(emit, extra)
+	}
+
+	var metrics *inspector.Metrics
 	if CLI.RelayMetrics {
 		targetBackend := CLI.Upstream
 		if targetBackend == "" {
@@ -124,10 +230,17 @@ func run() error {
 		}
 	}
 
-	inspect := inspector.NewInspector(
+	opts := []inspector.Option{
 		inspector.WithEmitter(emit),
 		inspector.WithMetrics(metrics),
-	)
+	}
+	switch {
+	case CLI.RejectInvalid:
+		opts = append(opts, inspector.WithRejectInvalid())
+	case CLI.Validate:
+		opts = append(opts, inspector.WithValidation())
+	}
+	inspect := inspector.NewInspector(opts...)
 
 	proxies := make([]proxy.Proxy, 0)
 	if CLI.ListenHttp != "" {
@@ -144,6 +257,24 @@ func run() error {
 		proxies = append(proxies, proxy.NewOTLPProxy(CLI.Listen, CLI.Upstream, inspect))
 	}
 
+	if CLI.ListenZipkin != "" {
+		if CLI.UpstreamZipkin == "" {
+			log.Printf("Warning: --listen-zipkin provided without --upstream-zipkin, signals will not be forwarded to an upstream %s proxy", zipkin)
+		}
+		var zipkinOpts []proxy.ZipkinOption
+		if CLI.ZipkinAsOtlp {
+			zipkinOpts = append(zipkinOpts, proxy.WithOTLPUpstream())
+		}
+		proxies = append(proxies, proxy.NewZipkinProxy(CLI.ListenZipkin, CLI.UpstreamZipkin, inspect, zipkinOpts...))
+	}
+
+	if CLI.ListenPrometheus != "" {
+		if CLI.UpstreamPrometheus == "" {
+			log.Printf("Warning: --listen-prometheus provided without --upstream-prometheus, signals will not be forwarded to an upstream %s proxy", prom)
+		}
+		proxies = append(proxies, proxy.NewPrometheusProxy(CLI.ListenPrometheus, CLI.UpstreamPrometheus, inspect))
+	}
+
 	waitErr := make(chan error, len(proxies))
 	for _, p := range proxies {
 		if err := p.Start(); err != nil {
@@ -174,6 +305,17 @@ func run() error {
 			}
 			return nil
 
+		case <-done:
+			log.Printf("Capture duration elapsed, shutting down...")
+
+			stopProxies(proxies)
+
+			err := <-waitErr
+			if err != nil {
+				return fmt.Errorf("server stopped with error: %w", err)
+			}
+			return nil
+
 		case err := <-waitErr:
 			if err != nil {
 				stopProxies(proxies)
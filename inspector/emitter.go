@@ -0,0 +1,67 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+// Emitter is the sink for every request observed by an Inspector.
+// Implementations must be safe for concurrent use; proxies call Inspect* from their request goroutines.
+type Emitter interface {
+	EmitTrace(ctx context.Context, req *collectortrace.ExportTraceServiceRequest) error
+	EmitMetric(ctx context.Context, req *collectormetrics.ExportMetricsServiceRequest) error
+	EmitLog(ctx context.Context, req *collectorlogs.ExportLogsServiceRequest) error
+}
+
+type NoopEmitter struct{}
+
+func NewNoopEmitter() Emitter {
+	return &NoopEmitter{}
+}
+
+func (e *NoopEmitter) EmitTrace(_ context.Context, _ *collectortrace.ExportTraceServiceRequest) error {
+	return nil
+}
+
+func (e *NoopEmitter) EmitMetric(_ context.Context, _ *collectormetrics.ExportMetricsServiceRequest) error {
+	return nil
+}
+
+func (e *NoopEmitter) EmitLog(_ context.Context, _ *collectorlogs.ExportLogsServiceRequest) error {
+	return nil
+}
+
+// MultiEmitter sends each request to every emitter in order, joining any errors.
+func MultiEmitter(emitters ...Emitter) Emitter {
+	return multiEmitter(emitters)
+}
+
+type multiEmitter []Emitter
+
+func (m multiEmitter) EmitTrace(ctx context.Context, req *collectortrace.ExportTraceServiceRequest) error {
+	var errs []error
+	for _, e := range m {
+		errs = append(errs, e.EmitTrace(ctx, req))
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiEmitter) EmitMetric(ctx context.Context, req *collectormetrics.ExportMetricsServiceRequest) error {
+	var errs []error
+	for _, e := range m {
+		errs = append(errs, e.EmitMetric(ctx, req))
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiEmitter) EmitLog(ctx context.Context, req *collectorlogs.ExportLogsServiceRequest) error {
+	var errs []error
+	for _, e := range m {
+		errs = append(errs, e.EmitLog(ctx, req))
+	}
+	return errors.Join(errs...)
+}
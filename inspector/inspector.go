@@ -8,8 +8,7 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/jimschubert/otel-relay/internal/emitter"
-	"github.com/jimschubert/otel-relay/internal/observe"
+	"github.com/jimschubert/otel-relay/internal/analyzer"
 	"go.opentelemetry.io/otel/metric"
 	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
 	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
@@ -21,30 +20,46 @@ import (
 type unmarshaler = func([]byte, proto.Message) error
 
 type Inspector struct {
-	emitter emitter.Emitter
-	metrics *observe.Metrics
+	emitter       Emitter
+	metrics       *Metrics
+	validator     analyzer.Analyzer
+	rejectInvalid bool
 }
 
 func NewInspector(opts ...Option) *Inspector {
 	options := &Options{
-		emitter: emitter.NewNoopEmitter(),
+		emitter: NewNoopEmitter(),
 	}
 
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	return &Inspector{
-		emitter: options.emitter,
-		metrics: options.metrics,
+	if options.emitter == nil {
+		options.emitter = NewNoopEmitter()
 	}
+	if options.metrics == nil {
+		options.metrics = &Metrics{}
+	}
+
+	insp := &Inspector{
+		emitter:       options.emitter,
+		metrics:       options.metrics,
+		rejectInvalid: options.rejectInvalid,
+	}
+	if options.validate {
+		insp.validator = analyzer.NewValidator()
+	}
+	return insp
 }
 
-func (i *Inspector) InspectHttpRequest(req *http.Request) {
+// InspectHttpRequest inspects an OTLP/HTTP request without consuming its body.
+// It returns a *ValidationError when the request is invalid and the Inspector rejects invalid requests.
+func (i *Inspector) InspectHttpRequest(req *http.Request) error {
 	if req.URL.Path != "/v1/traces" &&
 		req.URL.Path != "/v1/metrics" &&
 		req.URL.Path != "/v1/logs" {
-		return
+		return nil
 	}
 
 	contentType := req.Header.Get("Content-Type")
@@ -53,7 +68,7 @@ func (i *Inspector) InspectHttpRequest(req *http.Request) {
 	body, err := io.ReadAll(req.Body)
 	if err != nil {
 		log.Printf("Error reading request body: %v", err)
-		return
+		return nil
 	}
 	req.Body = io.NopCloser(bytes.NewReader(body))
 
@@ -65,60 +80,93 @@ func (i *Inspector) InspectHttpRequest(req *http.Request) {
 		unmarshal = protojson.Unmarshal
 	}
 
-	ctx := context.Background()
+	ctx := req.Context()
 	switch req.URL.Path {
 	case "/v1/traces":
 		var traceReq collectortrace.ExportTraceServiceRequest
-		if err := unmarshal(body, &traceReq); err == nil {
-			incrementMetric(ctx, i.metrics.HttpTracesRecv)
-			i.InspectTraces(&traceReq)
+		if err := unmarshal(body, &traceReq); err != nil {
+			return i.decodeError(ctx, req.URL.Path, err)
 		}
+		incrementMetric(ctx, i.metrics.HttpTracesRecv)
+		return i.InspectTraces(ctx, &traceReq)
 	case "/v1/metrics":
 		var metricReq collectormetrics.ExportMetricsServiceRequest
-		if err := unmarshal(body, &metricReq); err == nil {
-			incrementMetric(ctx, i.metrics.HttpMetricsRecv)
-			i.InspectMetrics(&metricReq)
+		if err := unmarshal(body, &metricReq); err != nil {
+			return i.decodeError(ctx, req.URL.Path, err)
 		}
-	case "/v1/logs":
+		incrementMetric(ctx, i.metrics.HttpMetricsRecv)
+		return i.InspectMetrics(ctx, &metricReq)
+	default:
 		var logReq collectorlogs.ExportLogsServiceRequest
-		if err := unmarshal(body, &logReq); err == nil {
-			incrementMetric(ctx, i.metrics.HttpLogsRecv)
-			i.InspectLogs(&logReq)
+		if err := unmarshal(body, &logReq); err != nil {
+			return i.decodeError(ctx, req.URL.Path, err)
 		}
+		incrementMetric(ctx, i.metrics.HttpLogsRecv)
+		return i.InspectLogs(ctx, &logReq)
+	}
+}
+
+// decodeError reports a body that could not be decoded. Such bodies are only rejected when validating,
+// since the upstream may accept payloads this relay does not understand.
+func (i *Inspector) decodeError(ctx context.Context, path string, err error) error {
+	if i.validator == nil {
+		return nil
 	}
+	log.Printf("Error decoding %s request: %v", path, err)
+	_, err = i.flag(ctx, []analyzer.Finding{{
+		Rule:     analyzer.RuleDecode,
+		Severity: analyzer.SeverityError,
+		Message:  err.Error(),
+		Target:   path,
+	}})
+	return err
 }
 
-func (i *Inspector) InspectTraces(req *collectortrace.ExportTraceServiceRequest) {
-	ctx := context.Background()
+// InspectTraces emits the request. It returns a *ValidationError when the request is invalid and the Inspector
+// rejects invalid requests; the request is emitted either way. Emitting isn't canceled with ctx, so a client that
+// gives up on its export doesn't stop the relay from observing it.
+func (i *Inspector) InspectTraces(ctx context.Context, req *collectortrace.ExportTraceServiceRequest) error {
 	incrementMetric(ctx, i.metrics.GrpcTracesRecv)
-	if err := i.emitter.EmitTrace(req); err != nil {
+	ctx, invalid := i.validate(ctx, func(a analyzer.Analyzer) []analyzer.Finding {
+		return analyzer.AnalyzeTraces(a, req)
+	})
+	if err := i.emitter.EmitTrace(context.WithoutCancel(ctx), req); err != nil {
 		log.Printf("Error emitting trace: %v", err)
 		incrementMetric(ctx, i.metrics.EventsDropped)
 	} else {
 		incrementMetric(ctx, i.metrics.EventsWritten)
 	}
+	return invalid
 }
 
-func (i *Inspector) InspectLogs(req *collectorlogs.ExportLogsServiceRequest) {
-	ctx := context.Background()
+// InspectLogs emits the request. See InspectTraces.
+func (i *Inspector) InspectLogs(ctx context.Context, req *collectorlogs.ExportLogsServiceRequest) error {
 	incrementMetric(ctx, i.metrics.GrpcLogsRecv)
-	if err := i.emitter.EmitLog(req); err != nil {
+	ctx, invalid := i.validate(ctx, func(a analyzer.Analyzer) []analyzer.Finding {
+		return analyzer.AnalyzeLogs(a, req)
+	})
+	if err := i.emitter.EmitLog(context.WithoutCancel(ctx), req); err != nil {
 		log.Printf("Error emitting log: %v", err)
 		incrementMetric(ctx, i.metrics.EventsDropped)
 	} else {
 		incrementMetric(ctx, i.metrics.EventsWritten)
 	}
+	return invalid
 }
 
-func (i *Inspector) InspectMetrics(req *collectormetrics.ExportMetricsServiceRequest) {
-	ctx := context.Background()
+// InspectMetrics emits the request. See InspectTraces.
+func (i *Inspector) InspectMetrics(ctx context.Context, req *collectormetrics.ExportMetricsServiceRequest) error {
 	incrementMetric(ctx, i.metrics.GrpcMetricsRecv)
-	if err := i.emitter.EmitMetric(req); err != nil {
+	ctx, invalid := i.validate(ctx, func(a analyzer.Analyzer) []analyzer.Finding {
+		return analyzer.AnalyzeMetrics(a, req)
+	})
+	if err := i.emitter.EmitMetric(context.WithoutCancel(ctx), req); err != nil {
 		log.Printf("Error emitting metric: %v", err)
 		incrementMetric(ctx, i.metrics.EventsDropped)
 	} else {
 		incrementMetric(ctx, i.metrics.EventsWritten)
 	}
+	return invalid
 }
 
 func incrementMetric(ctx context.Context, counter interface {
@@ -0,0 +1,51 @@
+package inspector
+
+import (
+	"context"
+	"testing"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestInspectEmitsAfterClientCancels(t *testing.T) {
+	emitter := &contextEmitter{}
+	insp := NewInspector(WithEmitter(emitter), WithRejectInvalid())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := insp.InspectTraces(ctx, &collectortrace.ExportTraceServiceRequest{ResourceSpans: []*prototrace.ResourceSpans{{
+		ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{{Name: "checkout"}}}},
+	}}})
+	if err == nil {
+		t.Fatal("expected the span without IDs to be rejected")
+	}
+	if emitter.err != nil {
+		t.Errorf("got emit context error %v, want it independent of the client", emitter.err)
+	}
+	if len(emitter.violations) == 0 {
+		t.Error("expected the violations to reach the emitter")
+	}
+}
+
+// contextEmitter records the context of the last emitted trace.
+type contextEmitter struct {
+	err        error
+	violations []Violation
+}
+
+func (c *contextEmitter) EmitTrace(ctx context.Context, _ *collectortrace.ExportTraceServiceRequest) error {
+	c.err = ctx.Err()
+	c.violations = ViolationsFromContext(ctx)
+	return nil
+}
+
+func (c *contextEmitter) EmitMetric(context.Context, *collectormetrics.ExportMetricsServiceRequest) error {
+	return nil
+}
+
+func (c *contextEmitter) EmitLog(context.Context, *collectorlogs.ExportLogsServiceRequest) error {
+	return nil
+}
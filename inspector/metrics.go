@@ -0,0 +1,55 @@
+package inspector
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Metrics are the counters an Inspector records about its own work. Any nil counter is skipped.
+type Metrics struct {
+	EventsDropped   metric.Int64Counter
+	EventsWritten   metric.Int64Counter
+	GrpcTracesRecv  metric.Int64Counter
+	GrpcMetricsRecv metric.Int64Counter
+	GrpcLogsRecv    metric.Int64Counter
+	HttpTracesRecv  metric.Int64Counter
+	HttpMetricsRecv metric.Int64Counter
+	HttpLogsRecv    metric.Int64Counter
+	InvalidRequests metric.Int64Counter
+}
+
+// NewMetrics creates all relay counters from the given meter.
+func NewMetrics(meter metric.Meter) (*Metrics, error) {
+	metrics := &Metrics{}
+
+	createCounter := func(name, desc string) (metric.Int64Counter, error) {
+		return meter.Int64Counter(name, metric.WithDescription(desc))
+	}
+
+	counters := []struct {
+		target *metric.Int64Counter
+		name   string
+		desc   string
+	}{
+		{&metrics.EventsDropped, "relay.events_dropped_total", "Total number of events dropped"},
+		{&metrics.EventsWritten, "relay.events_written_total", "Total number of events written to unix socket"},
+		{&metrics.GrpcTracesRecv, "relay.grpc_traces_received_total", "Total number of trace signals received via gRPC"},
+		{&metrics.GrpcMetricsRecv, "relay.grpc_metrics_received_total", "Total number of metric signals received via gRPC"},
+		{&metrics.GrpcLogsRecv, "relay.grpc_logs_received_total", "Total number of log signals received via gRPC"},
+		{&metrics.HttpTracesRecv, "relay.http_traces_received_total", "Total number of trace signals received via HTTP"},
+		{&metrics.HttpMetricsRecv, "relay.http_metrics_received_total", "Total number of metric signals received via HTTP"},
+		{&metrics.HttpLogsRecv, "relay.http_logs_received_total", "Total number of log signals received via HTTP"},
+		{&metrics.InvalidRequests, "relay.invalid_requests_total", "Total number of requests that violate the OTLP specification"},
+	}
+
+	var err error
+	for _, c := range counters {
+		*c.target, err = createCounter(c.name, c.desc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create counter %s: %w", c.name, err)
+		}
+	}
+
+	return metrics, nil
+}
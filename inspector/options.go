@@ -1,25 +1,40 @@
 package inspector
 
-import (
-	"github.com/jimschubert/otel-relay/internal/emitter"
-	"github.com/jimschubert/otel-relay/internal/observe"
-)
-
 type Options struct {
-	emitter emitter.Emitter
-	metrics *observe.Metrics
+	emitter       Emitter
+	metrics       *Metrics
+	validate      bool
+	rejectInvalid bool
 }
 
 type Option func(*Options)
 
-func WithEmitter(emitter emitter.Emitter) Option {
+// WithEmitter sets the sink that receives every inspected request. Defaults to a NoopEmitter.
+func WithEmitter(emitter Emitter) Option {
 	return func(opts *Options) {
 		opts.emitter = emitter
 	}
 }
 
-func WithMetrics(metrics *observe.Metrics) Option {
+// WithMetrics sets the counters used to record the Inspector's own activity. See NewMetrics.
+func WithMetrics(metrics *Metrics) Option {
 	return func(opts *Options) {
 		opts.metrics = metrics
 	}
 }
+
+// WithValidation checks every request against the OTLP specification and flags violations for the emitter. See ViolationsFromContext.
+func WithValidation() Option {
+	return func(opts *Options) {
+		opts.validate = true
+	}
+}
+
+// WithRejectInvalid validates every request, like WithValidation, and additionally fails requests that have violations.
+// Invalid requests are still emitted, but are not forwarded upstream.
+func WithRejectInvalid() Option {
+	return func(opts *Options) {
+		opts.validate = true
+		opts.rejectInvalid = true
+	}
+}
@@ -0,0 +1,76 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jimschubert/otel-relay/internal/analyzer"
+)
+
+// Violation is a structural OTLP spec violation found in an inspected request.
+type Violation struct {
+	Rule    string
+	Message string
+	// Target describes the offending item, e.g. `span "GET /users"`.
+	Target  string
+	Service string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s (%s)", v.Target, v.Message, v.Rule)
+}
+
+// ValidationError is returned by the Inspect methods when the Inspector rejects invalid requests. See WithRejectInvalid.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.String())
+	}
+	return fmt.Sprintf("request violates the OTLP specification: %s", strings.Join(messages, "; "))
+}
+
+type violationsKey struct{}
+
+// ViolationsFromContext returns the violations found in the request being emitted.
+// Emitters can use it to flag invalid requests; it returns nil when validation is disabled or the request is valid.
+func ViolationsFromContext(ctx context.Context) []Violation {
+	violations, _ := ctx.Value(violationsKey{}).([]Violation)
+	return violations
+}
+
+// validate runs the validator, when enabled, and attaches any violations to the returned context.
+// The error is non-nil only when violations were found and the Inspector rejects invalid requests.
+func (i *Inspector) validate(ctx context.Context, analyze func(analyzer.Analyzer) []analyzer.Finding) (context.Context, error) {
+	if i.validator == nil {
+		return ctx, nil
+	}
+	return i.flag(ctx, analyze(i.validator))
+}
+
+func (i *Inspector) flag(ctx context.Context, findings []analyzer.Finding) (context.Context, error) {
+	if len(findings) == 0 {
+		return ctx, nil
+	}
+
+	violations := make([]Violation, 0, len(findings))
+	for _, f := range findings {
+		violations = append(violations, Violation{
+			Rule:    f.Rule,
+			Message: f.Message,
+			Target:  f.Target,
+			Service: f.Service,
+		})
+	}
+
+	incrementMetric(ctx, i.metrics.InvalidRequests)
+	ctx = context.WithValue(ctx, violationsKey{}, violations)
+	if i.rejectInvalid {
+		return ctx, &ValidationError{Violations: violations}
+	}
+	return ctx, nil
+}
@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"github.com/jimschubert/otel-relay/internal/assembler"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Message  string
+	// Target describes the item the finding is about, e.g. `span "GET /users"`.
+	Target string
+	// Service is the service.name of the item's resource, filled in by the Analyze* walkers.
+	Service string
+	// SpanID is the hex ID of the span a trace-level finding is about, so it can be shown alongside that span.
+	SpanID string
+}
+
+// Analyzer reports findings about individual telemetry items.
+type Analyzer interface {
+	Resource(*resourcepb.Resource) []Finding
+	Span(*prototrace.Span) []Finding
+	Metric(*protometrics.Metric) []Finding
+	LogRecord(*protologs.LogRecord) []Finding
+}
+
+// TraceAnalyzer reports findings about a reassembled trace as a whole.
+type TraceAnalyzer interface {
+	Trace(*assembler.Trace) []Finding
+}
+
+func AnalyzeTraces(a Analyzer, req *collectortrace.ExportTraceServiceRequest) []Finding {
+	findings := make([]Finding, 0)
+	for _, rs := range req.ResourceSpans {
+		service := ServiceName(rs.Resource)
+		findings = withService(findings, service, a.Resource(rs.Resource))
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				findings = withService(findings, service, a.Span(span))
+			}
+		}
+	}
+	return findings
+}
+
+func AnalyzeMetrics(a Analyzer, req *collectormetrics.ExportMetricsServiceRequest) []Finding {
+	findings := make([]Finding, 0)
+	for _, rm := range req.ResourceMetrics {
+		service := ServiceName(rm.Resource)
+		findings = withService(findings, service, a.Resource(rm.Resource))
+		for _, sm := range rm.ScopeMetrics {
+			for _, metric := range sm.Metrics {
+				findings = withService(findings, service, a.Metric(metric))
+			}
+		}
+	}
+	return findings
+}
+
+func AnalyzeLogs(a Analyzer, req *collectorlogs.ExportLogsServiceRequest) []Finding {
+	findings := make([]Finding, 0)
+	for _, rl := range req.ResourceLogs {
+		service := ServiceName(rl.Resource)
+		findings = withService(findings, service, a.Resource(rl.Resource))
+		for _, sl := range rl.ScopeLogs {
+			for _, record := range sl.LogRecords {
+				findings = withService(findings, service, a.LogRecord(record))
+			}
+		}
+	}
+	return findings
+}
+
+func withService(dst []Finding, service string, findings []Finding) []Finding {
+	for _, f := range findings {
+		f.Service = service
+		dst = append(dst, f)
+	}
+	return dst
+}
+
+// ServiceName returns the resource's service.name, or "" when unset.
+func ServiceName(res *resourcepb.Resource) string {
+	if res == nil {
+		return ""
+	}
+	for _, kv := range res.Attributes {
+		if kv.Key == "service.name" {
+			return kv.Value.GetStringValue()
+		}
+	}
+	return ""
+}
+
+// DataPointAttributes returns the attributes of each of a metric's data points, whatever its type.
+func DataPointAttributes(metric *protometrics.Metric) [][]*commonpb.KeyValue {
+	attrs := make([][]*commonpb.KeyValue, 0)
+	switch data := metric.Data.(type) {
+	case *protometrics.Metric_Gauge:
+		for _, dp := range data.Gauge.DataPoints {
+			attrs = append(attrs, dp.Attributes)
+		}
+	case *protometrics.Metric_Sum:
+		for _, dp := range data.Sum.DataPoints {
+			attrs = append(attrs, dp.Attributes)
+		}
+	case *protometrics.Metric_Histogram:
+		for _, dp := range data.Histogram.DataPoints {
+			attrs = append(attrs, dp.Attributes)
+		}
+	case *protometrics.Metric_ExponentialHistogram:
+		for _, dp := range data.ExponentialHistogram.DataPoints {
+			attrs = append(attrs, dp.Attributes)
+		}
+	case *protometrics.Metric_Summary:
+		for _, dp := range data.Summary.DataPoints {
+			attrs = append(attrs, dp.Attributes)
+		}
+	}
+	return attrs
+}
+
+func lookup(attrs []*commonpb.KeyValue, key string) (*commonpb.AnyValue, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return nil, false
+}
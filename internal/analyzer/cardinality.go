@@ -0,0 +1,233 @@
+package analyzer
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	_ Analyzer = (*Cardinality)(nil)
+)
+
+const (
+	RuleCardinality = "cardinality/threshold"
+
+	// DefaultCardinalityThreshold is the number of series per metric name above which Cardinality warns.
+	DefaultCardinalityThreshold = 1000
+
+	// growthWindow is the period MetricCardinality.Growth covers.
+	growthWindow = time.Minute
+	// growthSampleInterval is how often a metric's series count is sampled to measure growth.
+	growthSampleInterval = 5 * time.Second
+)
+
+// Cardinality tracks the unique attribute sets (series) of each metric name, and the unique values of each attribute
+// key, across every request it sees. It warns when a metric's series count crosses the threshold, and again each time
+// the count doubles. Attribute sets and values are stored as 64-bit hashes, so memory grows with cardinality, but slowly.
+type Cardinality struct {
+	threshold int
+	now       func() time.Time
+
+	mu      sync.Mutex
+	metrics map[string]*metricSeries
+}
+
+type metricSeries struct {
+	series    map[uint64]struct{}
+	values    map[string]map[uint64]struct{}
+	firstSeen time.Time
+	// warnAt is the series count of the next warning.
+	warnAt  int
+	samples []growthSample
+}
+
+type growthSample struct {
+	at     time.Time
+	series int
+}
+
+// MetricCardinality describes the cardinality of one metric name.
+type MetricCardinality struct {
+	Name   string
+	Series int
+	// Growth is the number of series first seen within the last minute.
+	Growth    int
+	FirstSeen time.Time
+	// Keys are the metric's attribute keys, most unique values first.
+	Keys []KeyCardinality
+}
+
+type KeyCardinality struct {
+	Key    string
+	Values int
+}
+
+// NewCardinality warns about metrics with more than threshold series. A threshold of 0 or less uses
+// DefaultCardinalityThreshold.
+func NewCardinality(threshold int) *Cardinality {
+	if threshold <= 0 {
+		threshold = DefaultCardinalityThreshold
+	}
+	return &Cardinality{
+		threshold: threshold,
+		now:       time.Now,
+		metrics:   make(map[string]*metricSeries),
+	}
+}
+
+// Threshold is the series count above which a metric is reported.
+func (c *Cardinality) Threshold() int {
+	return c.threshold
+}
+
+func (c *Cardinality) Resource(*resourcepb.Resource) []Finding {
+	return nil
+}
+
+func (c *Cardinality) Span(*prototrace.Span) []Finding {
+	return nil
+}
+
+func (c *Cardinality) LogRecord(*protologs.LogRecord) []Finding {
+	return nil
+}
+
+func (c *Cardinality) Metric(metric *protometrics.Metric) []Finding {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	m := c.metrics[metric.Name]
+	if m == nil {
+		m = &metricSeries{
+			series:    make(map[uint64]struct{}),
+			values:    make(map[string]map[uint64]struct{}),
+			firstSeen: now,
+			warnAt:    c.threshold + 1,
+		}
+		c.metrics[metric.Name] = m
+	}
+
+	for _, attrs := range DataPointAttributes(metric) {
+		m.add(attrs)
+	}
+	m.sample(now)
+
+	if len(m.series) < m.warnAt {
+		return nil
+	}
+	for m.warnAt <= len(m.series) {
+		m.warnAt *= 2
+	}
+
+	message := fmt.Sprintf("%d unique attribute sets exceed the cardinality threshold of %d", len(m.series), c.threshold)
+	if keys := m.keys(); len(keys) > 0 {
+		message += fmt.Sprintf("; %q has the most values (%d)", keys[0].Key, keys[0].Values)
+	}
+	return []Finding{{
+		Rule:     RuleCardinality,
+		Severity: SeverityWarning,
+		Message:  message,
+		Target:   fmt.Sprintf("metric %q", metric.Name),
+	}}
+}
+
+// Top returns the n metrics with the most series, or all of them when n is 0 or less.
+func (c *Cardinality) Top(n int) []MetricCardinality {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	top := make([]MetricCardinality, 0, len(c.metrics))
+	for name, m := range c.metrics {
+		top = append(top, MetricCardinality{
+			Name:      name,
+			Series:    len(m.series),
+			Growth:    m.growth(now),
+			FirstSeen: m.firstSeen,
+			Keys:      m.keys(),
+		})
+	}
+	slices.SortFunc(top, func(a, b MetricCardinality) int {
+		return cmp.Or(cmp.Compare(b.Series, a.Series), cmp.Compare(a.Name, b.Name))
+	})
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+func (m *metricSeries) add(attrs []*commonpb.KeyValue) {
+	sorted := slices.SortedFunc(slices.Values(attrs), func(a, b *commonpb.KeyValue) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	set := fnv.New64a()
+	for _, kv := range sorted {
+		value := hashValue(kv.Value)
+		fmt.Fprintf(set, "%s\x00%d\x00", kv.Key, value)
+
+		if m.values[kv.Key] == nil {
+			m.values[kv.Key] = make(map[uint64]struct{})
+		}
+		m.values[kv.Key][value] = struct{}{}
+	}
+	m.series[set.Sum64()] = struct{}{}
+}
+
+// sample records the series count at most every growthSampleInterval, keeping a growthWindow of history.
+func (m *metricSeries) sample(now time.Time) {
+	if n := len(m.samples); n > 0 && now.Sub(m.samples[n-1].at) < growthSampleInterval {
+		return
+	}
+	m.samples = append(m.samples, growthSample{at: now, series: len(m.series)})
+
+	// keep one sample older than the window, so growth covers all of it
+	drop := 0
+	for drop+1 < len(m.samples) && now.Sub(m.samples[drop+1].at) >= growthWindow {
+		drop++
+	}
+	m.samples = m.samples[drop:]
+}
+
+// growth compares the series count to the latest sample taken before the window; a metric younger than the window
+// grew by all of its series.
+func (m *metricSeries) growth(now time.Time) int {
+	baseline := 0
+	for _, s := range m.samples {
+		if now.Sub(s.at) < growthWindow {
+			break
+		}
+		baseline = s.series
+	}
+	return len(m.series) - baseline
+}
+
+func (m *metricSeries) keys() []KeyCardinality {
+	keys := make([]KeyCardinality, 0, len(m.values))
+	for key, values := range m.values {
+		keys = append(keys, KeyCardinality{Key: key, Values: len(values)})
+	}
+	slices.SortFunc(keys, func(a, b KeyCardinality) int {
+		return cmp.Or(cmp.Compare(b.Values, a.Values), cmp.Compare(a.Key, b.Key))
+	})
+	return keys
+}
+
+func hashValue(value *commonpb.AnyValue) uint64 {
+	h := fnv.New64a()
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(value)
+	_, _ = h.Write(b)
+	return h.Sum64()
+}
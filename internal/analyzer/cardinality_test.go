@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+func TestCardinalityWarnings(t *testing.T) {
+	c := NewCardinality(3)
+	var got []string
+	for user := range 9 {
+		for _, f := range c.Metric(requestsMetric(user)) {
+			got = append(got, strconv.Itoa(user)+": "+f.Message)
+		}
+	}
+	want := []string{
+		`3: 4 unique attribute sets exceed the cardinality threshold of 3; "user.id" has the most values (4)`,
+		`7: 8 unique attribute sets exceed the cardinality threshold of 3; "user.id" has the most values (8)`,
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got warnings %q, want %q", got, want)
+	}
+
+	if findings := c.Metric(requestsMetric(0)); len(findings) != 0 {
+		t.Errorf("got %v for a repeated series, want none", findings)
+	}
+}
+
+func TestCardinalityGrowth(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	c := NewCardinality(0)
+	c.now = func() time.Time { return now }
+
+	tests := []struct {
+		name       string
+		after      time.Duration
+		users      []int // sent as one export
+		wantSeries int
+		wantGrowth int
+	}{
+		{name: "first export", users: []int{0, 1}, wantSeries: 2, wantGrowth: 2},
+		{name: "younger than the window", after: 30 * time.Second, users: []int{2}, wantSeries: 3, wantGrowth: 3},
+		{name: "first export leaves the window", after: 70 * time.Second, users: []int{3}, wantSeries: 4, wantGrowth: 2},
+		{name: "no new series in the window", after: 200 * time.Second, wantSeries: 4, wantGrowth: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = start.Add(tt.after)
+			if len(tt.users) > 0 {
+				c.Metric(requestsMetric(tt.users...))
+			}
+
+			top := c.Top(0)
+			if len(top) != 1 {
+				t.Fatalf("got %d metrics, want 1", len(top))
+			}
+			if top[0].Series != tt.wantSeries || top[0].Growth != tt.wantGrowth {
+				t.Errorf("got %d series and growth %d, want %d and %d", top[0].Series, top[0].Growth, tt.wantSeries, tt.wantGrowth)
+			}
+			if !top[0].FirstSeen.Equal(start) {
+				t.Errorf("got first seen %v, want %v", top[0].FirstSeen, start)
+			}
+		})
+	}
+}
+
+// requestsMetric is a sum with a data point for each user, all sharing one route.
+func requestsMetric(users ...int) *protometrics.Metric {
+	points := make([]*protometrics.NumberDataPoint, 0, len(users))
+	for _, user := range users {
+		points = append(points, &protometrics.NumberDataPoint{Attributes: []*commonpb.KeyValue{
+			{Key: "route", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "/cart"}}},
+			{Key: "user.id", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(user)}}},
+		}})
+	}
+	return &protometrics.Metric{Name: "requests", Data: &protometrics.Metric_Sum{Sum: &protometrics.Sum{DataPoints: points}}}
+}
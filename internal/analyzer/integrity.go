@@ -0,0 +1,231 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jimschubert/otel-relay/internal/assembler"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+var (
+	_ TraceAnalyzer = (*Integrity)(nil)
+)
+
+const (
+	RuleOrphanSpan        = "trace/orphan"
+	RuleDuplicateSpanID   = "trace/duplicate-span-id"
+	RuleClockSkew         = "trace/clock-skew"
+	RuleBrokenPropagation = "trace/broken-propagation"
+
+	// DefaultClockSkew is how far a child may start before, or end after, its parent before Integrity reports it.
+	DefaultClockSkew = 100 * time.Millisecond
+
+	// maxRecentSpans bounds the client and root spans Integrity remembers to match across traces.
+	maxRecentSpans = 1000
+)
+
+// Integrity checks reassembled traces for the problems no single span shows: spans whose parent never arrived,
+// duplicate span IDs, children that start before or end after their parent by more than the allowed clock skew, and
+// broken context propagation.
+//
+// Broken propagation can't be seen within a single trace, since the downstream service starts a new one. Integrity
+// remembers recent client and producer spans without children, and recent server and consumer spans without parents,
+// and reports a root span from one service that runs entirely within a childless client span of another service's
+// trace.
+type Integrity struct {
+	skew time.Duration
+
+	mu      sync.Mutex
+	clients []spanRef
+	roots   []spanRef
+}
+
+type spanRef struct {
+	traceID []byte
+	span    *prototrace.Span
+	service string
+}
+
+// NewIntegrity allows children to start before or end after their parent by up to skew. A skew of 0 or less uses
+// DefaultClockSkew.
+func NewIntegrity(skew time.Duration) *Integrity {
+	if skew <= 0 {
+		skew = DefaultClockSkew
+	}
+	return &Integrity{skew: skew}
+}
+
+// Trace returns the trace's findings, each with the service and ID of the span it concerns.
+func (i *Integrity) Trace(trace *assembler.Trace) []Finding {
+	findings := make([]Finding, 0)
+
+	ids := make(map[string][]*assembler.Node)
+	trace.Walk(func(node *assembler.Node, _ int) {
+		ids[string(node.Span.SpanId)] = append(ids[string(node.Span.SpanId)], node)
+	})
+
+	trace.Walk(func(node *assembler.Node, _ int) {
+		findings = append(findings, i.orphan(node, ids)...)
+		findings = append(findings, i.clockSkew(node)...)
+	})
+	findings = append(findings, duplicates(trace, ids)...)
+	findings = append(findings, i.propagation(trace)...)
+	return findings
+}
+
+func (i *Integrity) orphan(node *assembler.Node, ids map[string][]*assembler.Node) []Finding {
+	if !node.Orphan {
+		return nil
+	}
+	message := fmt.Sprintf("parent span %x never arrived", node.Span.ParentSpanId)
+	if _, ok := ids[string(node.Span.ParentSpanId)]; ok {
+		message = fmt.Sprintf("parent span %x is its own ancestor", node.Span.ParentSpanId)
+	}
+	return []Finding{spanFinding(node, RuleOrphanSpan, SeverityWarning, message)}
+}
+
+// clockSkew compares node's children to it. A child that starts before its parent is always suspicious; one that ends
+// after its parent is only reported across services, since in-process async work may outlive its parent.
+func (i *Integrity) clockSkew(parent *assembler.Node) []Finding {
+	findings := make([]Finding, 0)
+	service := ServiceName(parent.Resource)
+	for _, child := range parent.Children {
+		childService := ServiceName(child.Resource)
+		across := ""
+		if childService != service {
+			across = fmt.Sprintf(" in %s", serviceOr(service))
+		}
+
+		if early := nanosBetween(child.Span.StartTimeUnixNano, parent.Span.StartTimeUnixNano); early > i.skew {
+			findings = append(findings, spanFinding(child, RuleClockSkew, SeverityWarning,
+				fmt.Sprintf("starts %v before its parent %q%s", early, parent.Span.Name, across)))
+		}
+		if late := nanosBetween(parent.Span.EndTimeUnixNano, child.Span.EndTimeUnixNano); late > i.skew && across != "" {
+			findings = append(findings, spanFinding(child, RuleClockSkew, SeverityWarning,
+				fmt.Sprintf("ends %v after its parent %q%s", late, parent.Span.Name, across)))
+		}
+	}
+	return findings
+}
+
+func duplicates(trace *assembler.Trace, ids map[string][]*assembler.Node) []Finding {
+	findings := make([]Finding, 0)
+	trace.Walk(func(node *assembler.Node, _ int) {
+		nodes := ids[string(node.Span.SpanId)]
+		if len(nodes) < 2 || nodes[0] != node {
+			return
+		}
+		names := make([]string, 0, len(nodes))
+		for _, n := range nodes {
+			names = append(names, fmt.Sprintf("%q", n.Span.Name))
+		}
+		findings = append(findings, spanFinding(node, RuleDuplicateSpanID, SeverityError,
+			fmt.Sprintf("span ID is shared by %d spans (%s)", len(nodes), strings.Join(names, ", "))))
+	})
+	return findings
+}
+
+// propagation matches the trace's childless client spans and parentless server spans against those of earlier traces.
+// Each span is matched at most once.
+func (i *Integrity) propagation(trace *assembler.Trace) []Finding {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	findings := make([]Finding, 0)
+	trace.Walk(func(node *assembler.Node, _ int) {
+		ref := spanRef{traceID: trace.TraceID, span: node.Span, service: ServiceName(node.Resource)}
+		switch {
+		case len(node.Children) == 0 && isOutgoing(node.Span.Kind):
+			if idx := slices.IndexFunc(i.roots, func(root spanRef) bool { return i.within(root, ref) }); idx >= 0 {
+				findings = append(findings, brokenPropagation(i.roots[idx], ref, trace.TraceID))
+				i.roots = slices.Delete(i.roots, idx, idx+1)
+				return
+			}
+			i.clients = remember(i.clients, ref)
+		case len(node.Span.ParentSpanId) == 0 && isIncoming(node.Span.Kind):
+			if idx := slices.IndexFunc(i.clients, func(client spanRef) bool { return i.within(ref, client) }); idx >= 0 {
+				findings = append(findings, brokenPropagation(ref, i.clients[idx], trace.TraceID))
+				i.clients = slices.Delete(i.clients, idx, idx+1)
+				return
+			}
+			i.roots = remember(i.roots, ref)
+		}
+	})
+	return findings
+}
+
+// within reports whether root, from another trace and service, ran inside client, allowing for clock skew.
+func (i *Integrity) within(root, client spanRef) bool {
+	if bytes.Equal(root.traceID, client.traceID) || root.service == client.service {
+		return false
+	}
+	skew := uint64(i.skew)
+	return root.span.StartTimeUnixNano+skew >= client.span.StartTimeUnixNano &&
+		root.span.EndTimeUnixNano <= client.span.EndTimeUnixNano+skew
+}
+
+// brokenPropagation reports the match on whichever of the two spans is in the current trace.
+func brokenPropagation(root, client spanRef, current []byte) Finding {
+	finding := Finding{Rule: RuleBrokenPropagation, Severity: SeverityWarning}
+	if bytes.Equal(current, client.traceID) {
+		finding.Message = fmt.Sprintf("%s started new trace %x with span %q during this span; trace context may not be propagated",
+			serviceOr(root.service), root.traceID, root.span.Name)
+		finding.Target = fmt.Sprintf("span %q", client.span.Name)
+		finding.Service = client.service
+		finding.SpanID = hex.EncodeToString(client.span.SpanId)
+		return finding
+	}
+	finding.Message = fmt.Sprintf("starts a new trace during span %q of %s in trace %x; trace context may not be propagated",
+		client.span.Name, serviceOr(client.service), client.traceID)
+	finding.Target = fmt.Sprintf("span %q", root.span.Name)
+	finding.Service = root.service
+	finding.SpanID = hex.EncodeToString(root.span.SpanId)
+	return finding
+}
+
+func remember(refs []spanRef, ref spanRef) []spanRef {
+	if len(refs) >= maxRecentSpans {
+		refs = slices.Delete(refs, 0, 1)
+	}
+	return append(refs, ref)
+}
+
+func isOutgoing(kind prototrace.Span_SpanKind) bool {
+	return kind == prototrace.Span_SPAN_KIND_CLIENT || kind == prototrace.Span_SPAN_KIND_PRODUCER
+}
+
+func isIncoming(kind prototrace.Span_SpanKind) bool {
+	return kind == prototrace.Span_SPAN_KIND_SERVER || kind == prototrace.Span_SPAN_KIND_CONSUMER
+}
+
+func spanFinding(node *assembler.Node, rule string, severity Severity, message string) Finding {
+	return Finding{
+		Rule:     rule,
+		Severity: severity,
+		Message:  message,
+		Target:   fmt.Sprintf("span %q", node.Span.Name),
+		Service:  ServiceName(node.Resource),
+		SpanID:   hex.EncodeToString(node.Span.SpanId),
+	}
+}
+
+// nanosBetween is how long after from the time to is, or 0 if it isn't after.
+func nanosBetween(from, to uint64) time.Duration {
+	if to <= from {
+		return 0
+	}
+	return time.Duration(to - from)
+}
+
+func serviceOr(service string) string {
+	if service == "" {
+		return "<no service.name>"
+	}
+	return service
+}
@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/jimschubert/otel-relay/internal/assembler"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestIntegrityTrace(t *testing.T) {
+	ms := uint64(time.Millisecond)
+	tests := []struct {
+		name  string
+		roots []*assembler.Node
+		want  []string
+	}{
+		{
+			name:  "well-formed",
+			roots: []*assembler.Node{spanNode("web", "checkout", 1, 0, 0, 1000*ms, spanNode("web", "charge", 2, 1, 10*ms, 900*ms))},
+		},
+		{
+			name:  "orphan",
+			roots: []*assembler.Node{orphanNode(spanNode("web", "charge", 2, 9, 0, 100*ms))},
+			want:  []string{"parent span 0909090909090909 never arrived"},
+		},
+		{
+			name:  "parent is its own ancestor",
+			roots: []*assembler.Node{orphanNode(spanNode("web", "charge", 2, 3, 0, 100*ms, spanNode("web", "retry", 3, 2, 0, 100*ms)))},
+			want:  []string{"parent span 0303030303030303 is its own ancestor"},
+		},
+		{
+			name:  "duplicate span ID",
+			roots: []*assembler.Node{spanNode("web", "checkout", 1, 0, 0, 100*ms), spanNode("web", "refund", 1, 0, 0, 100*ms)},
+			want:  []string{`span ID is shared by 2 spans ("checkout", "refund")`},
+		},
+		{
+			name:  "starts before its parent",
+			roots: []*assembler.Node{spanNode("web", "checkout", 1, 0, 500*ms, 1000*ms, spanNode("web", "charge", 2, 1, 300*ms, 900*ms))},
+			want:  []string{`starts 200ms before its parent "checkout"`},
+		},
+		{
+			name:  "starts before its parent in another service",
+			roots: []*assembler.Node{spanNode("web", "checkout", 1, 0, 500*ms, 1000*ms, spanNode("payments", "charge", 2, 1, 300*ms, 900*ms))},
+			want:  []string{`starts 200ms before its parent "checkout" in web`},
+		},
+		{
+			name:  "starts before its parent within the skew",
+			roots: []*assembler.Node{spanNode("web", "checkout", 1, 0, 500*ms, 1000*ms, spanNode("payments", "charge", 2, 1, 450*ms, 900*ms))},
+		},
+		{
+			name:  "ends after its parent in the same service",
+			roots: []*assembler.Node{spanNode("web", "checkout", 1, 0, 0, 1000*ms, spanNode("web", "flush", 2, 1, 10*ms, 5000*ms))},
+		},
+		{
+			name:  "ends after its parent in another service",
+			roots: []*assembler.Node{spanNode("web", "checkout", 1, 0, 0, 1000*ms, spanNode("payments", "charge", 2, 1, 10*ms, 1200*ms))},
+			want:  []string{`ends 200ms after its parent "checkout" in web`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := &assembler.Trace{TraceID: bytes.Repeat([]byte{1}, 16), Roots: tt.roots}
+			wantMessages(t, NewIntegrity(0).Trace(trace), tt.want)
+		})
+	}
+}
+
+func TestIntegrityBrokenPropagation(t *testing.T) {
+	ms := uint64(time.Millisecond)
+	client := func(service string) *assembler.Node {
+		node := spanNode(service, "GET /charge", 2, 1, 100*ms, 900*ms)
+		node.Span.Kind = prototrace.Span_SPAN_KIND_CLIENT
+		return spanNode(service, "checkout", 1, 0, 0, 1000*ms, node)
+	}
+	server := func(service string, start, end uint64) *assembler.Node {
+		node := spanNode(service, "POST /charge", 5, 0, start, end)
+		node.Span.Kind = prototrace.Span_SPAN_KIND_SERVER
+		return node
+	}
+	trace := func(id byte, root *assembler.Node) *assembler.Trace {
+		return &assembler.Trace{TraceID: bytes.Repeat([]byte{id}, 16), Roots: []*assembler.Node{root}}
+	}
+
+	type step struct {
+		trace *assembler.Trace
+		want  []string
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "server after client",
+			steps: []step{
+				{trace: trace(1, client("web"))},
+				{trace: trace(2, server("payments", 200*ms, 800*ms)), want: []string{
+					`starts a new trace during span "GET /charge" of web in trace 01010101010101010101010101010101; trace context may not be propagated`,
+				}},
+			},
+		},
+		{
+			name: "client after server",
+			steps: []step{
+				{trace: trace(2, server("payments", 200*ms, 800*ms))},
+				{trace: trace(1, client("web")), want: []string{
+					`payments started new trace 02020202020202020202020202020202 with span "POST /charge" during this span; trace context may not be propagated`,
+				}},
+			},
+		},
+		{
+			name: "each span is matched at most once",
+			steps: []step{
+				{trace: trace(1, client("web"))},
+				{trace: trace(2, server("payments", 200*ms, 800*ms)), want: []string{
+					`starts a new trace during span "GET /charge" of web in trace 01010101010101010101010101010101; trace context may not be propagated`,
+				}},
+				{trace: trace(3, server("payments", 300*ms, 700*ms))},
+			},
+		},
+		{
+			name: "same service",
+			steps: []step{
+				{trace: trace(1, client("web"))},
+				{trace: trace(2, server("web", 200*ms, 800*ms))},
+			},
+		},
+		{
+			name: "outside the client span",
+			steps: []step{
+				{trace: trace(1, client("web"))},
+				{trace: trace(2, server("payments", 200*ms, 1500*ms))},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			integrity := NewIntegrity(0)
+			for _, s := range tt.steps {
+				wantMessages(t, integrity.Trace(s.trace), s.want)
+			}
+		})
+	}
+}
+
+// spanNode builds a node for service with single-byte span and parent IDs; a parent of 0 makes a root.
+func spanNode(service, name string, id, parent byte, start, end uint64, children ...*assembler.Node) *assembler.Node {
+	span := &prototrace.Span{Name: name, SpanId: bytes.Repeat([]byte{id}, 8), StartTimeUnixNano: start, EndTimeUnixNano: end}
+	if parent != 0 {
+		span.ParentSpanId = bytes.Repeat([]byte{parent}, 8)
+	}
+	return &assembler.Node{
+		Span: span,
+		Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+			{Key: "service.name", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: service}}},
+		}},
+		Children: children,
+	}
+}
+
+func orphanNode(node *assembler.Node) *assembler.Node {
+	node.Orphan = true
+	return node
+}
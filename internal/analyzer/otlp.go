@@ -0,0 +1,237 @@
+package analyzer
+
+import (
+	"fmt"
+
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+var (
+	_ Analyzer = (*Validator)(nil)
+)
+
+const (
+	RuleTraceID       = "otlp/trace-id"
+	RuleSpanID        = "otlp/span-id"
+	RuleTimestamps    = "otlp/timestamps"
+	RuleRequiredField = "otlp/required-field"
+	RuleBuckets       = "otlp/histogram-buckets"
+	RuleCounts        = "otlp/counts"
+	RuleMetricData    = "otlp/metric-data"
+	RuleDecode        = "otlp/decode"
+)
+
+// Validator reports structural violations of the OTLP specification. Every finding is an error.
+type Validator struct{}
+
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// Resource has no structural rules to check.
+func (v *Validator) Resource(*resourcepb.Resource) []Finding {
+	return nil
+}
+
+func (v *Validator) Span(span *prototrace.Span) []Finding {
+	c := &checker{target: fmt.Sprintf("span %q", span.Name)}
+
+	c.traceID("trace_id", span.TraceId, true)
+	c.spanID("span_id", span.SpanId, true)
+	c.spanID("parent_span_id", span.ParentSpanId, false)
+	if span.Name == "" {
+		c.add(RuleRequiredField, "name is empty")
+	}
+	if span.StartTimeUnixNano == 0 || span.EndTimeUnixNano == 0 {
+		c.add(RuleTimestamps, "start_time_unix_nano and end_time_unix_nano are required")
+	} else if span.EndTimeUnixNano < span.StartTimeUnixNano {
+		c.add(RuleTimestamps, fmt.Sprintf("end time is %d ns before start time", span.StartTimeUnixNano-span.EndTimeUnixNano))
+	}
+
+	for idx, link := range span.Links {
+		c.traceID(fmt.Sprintf("links[%d].trace_id", idx), link.TraceId, true)
+		c.spanID(fmt.Sprintf("links[%d].span_id", idx), link.SpanId, true)
+	}
+	return c.findings
+}
+
+func (v *Validator) LogRecord(record *protologs.LogRecord) []Finding {
+	c := &checker{target: "log record"}
+
+	// trace context is optional on logs, but must be well-formed when present
+	c.traceID("trace_id", record.TraceId, false)
+	c.spanID("span_id", record.SpanId, false)
+	if len(record.SpanId) > 0 && len(record.TraceId) == 0 {
+		c.add(RuleTraceID, "span_id is set without trace_id")
+	}
+	return c.findings
+}
+
+func (v *Validator) Metric(metric *protometrics.Metric) []Finding {
+	c := &checker{target: fmt.Sprintf("metric %q", metric.Name)}
+
+	if metric.Name == "" {
+		c.add(RuleRequiredField, "name is empty")
+	}
+
+	switch data := metric.Data.(type) {
+	case *protometrics.Metric_Gauge:
+		for idx, dp := range data.Gauge.DataPoints {
+			c.numberDataPoint(idx, dp, false)
+		}
+	case *protometrics.Metric_Sum:
+		monotonic := data.Sum.IsMonotonic
+		for idx, dp := range data.Sum.DataPoints {
+			c.numberDataPoint(idx, dp, monotonic)
+		}
+	case *protometrics.Metric_Histogram:
+		for idx, dp := range data.Histogram.DataPoints {
+			c.histogramDataPoint(idx, dp)
+		}
+	case *protometrics.Metric_ExponentialHistogram:
+		for idx, dp := range data.ExponentialHistogram.DataPoints {
+			c.exponentialHistogramDataPoint(idx, dp)
+		}
+	case *protometrics.Metric_Summary:
+		for idx, dp := range data.Summary.DataPoints {
+			c.summaryDataPoint(idx, dp)
+		}
+	default:
+		c.add(RuleMetricData, "metric has no data (gauge, sum, histogram, exponential_histogram or summary)")
+	}
+	return c.findings
+}
+
+type checker struct {
+	target   string
+	findings []Finding
+}
+
+func (c *checker) add(rule, message string) {
+	c.findings = append(c.findings, Finding{
+		Rule:     rule,
+		Severity: SeverityError,
+		Message:  message,
+		Target:   c.target,
+	})
+}
+
+func (c *checker) traceID(field string, id []byte, required bool) {
+	switch {
+	case len(id) == 0 && !required:
+	case len(id) != 16:
+		c.add(RuleTraceID, fmt.Sprintf("%s must be 16 bytes, got %d", field, len(id)))
+	case allZero(id):
+		c.add(RuleTraceID, fmt.Sprintf("%s is all zeroes", field))
+	}
+}
+
+func (c *checker) spanID(field string, id []byte, required bool) {
+	switch {
+	case len(id) == 0 && !required:
+	case len(id) != 8:
+		c.add(RuleSpanID, fmt.Sprintf("%s must be 8 bytes, got %d", field, len(id)))
+	case allZero(id):
+		c.add(RuleSpanID, fmt.Sprintf("%s is all zeroes", field))
+	}
+}
+
+func (c *checker) dataPoint(idx int, start, ts uint64) {
+	if ts == 0 {
+		c.add(RuleTimestamps, fmt.Sprintf("data_points[%d].time_unix_nano is required", idx))
+	} else if start > ts {
+		c.add(RuleTimestamps, fmt.Sprintf("data_points[%d] time is before its start time", idx))
+	}
+}
+
+func (c *checker) numberDataPoint(idx int, dp *protometrics.NumberDataPoint, monotonic bool) {
+	c.dataPoint(idx, dp.StartTimeUnixNano, dp.TimeUnixNano)
+	switch v := dp.Value.(type) {
+	case nil:
+		if dp.Flags&uint32(protometrics.DataPointFlags_DATA_POINT_FLAGS_NO_RECORDED_VALUE_MASK) == 0 {
+			c.add(RuleMetricData, fmt.Sprintf("data_points[%d] has no value", idx))
+		}
+	case *protometrics.NumberDataPoint_AsInt:
+		if monotonic && v.AsInt < 0 {
+			c.add(RuleCounts, fmt.Sprintf("data_points[%d] of a monotonic sum is negative (%d)", idx, v.AsInt))
+		}
+	case *protometrics.NumberDataPoint_AsDouble:
+		if monotonic && v.AsDouble < 0 {
+			c.add(RuleCounts, fmt.Sprintf("data_points[%d] of a monotonic sum is negative (%g)", idx, v.AsDouble))
+		}
+	}
+}
+
+func (c *checker) histogramDataPoint(idx int, dp *protometrics.HistogramDataPoint) {
+	c.dataPoint(idx, dp.StartTimeUnixNano, dp.TimeUnixNano)
+
+	if len(dp.BucketCounts) > 0 && len(dp.BucketCounts) != len(dp.ExplicitBounds)+1 {
+		c.add(RuleBuckets, fmt.Sprintf("data_points[%d] has %d bucket counts for %d explicit bounds, want %d",
+			idx, len(dp.BucketCounts), len(dp.ExplicitBounds), len(dp.ExplicitBounds)+1))
+	}
+	for b := 1; b < len(dp.ExplicitBounds); b++ {
+		if dp.ExplicitBounds[b] <= dp.ExplicitBounds[b-1] {
+			c.add(RuleBuckets, fmt.Sprintf("data_points[%d] explicit bounds are not strictly increasing at index %d", idx, b))
+			break
+		}
+	}
+	if len(dp.BucketCounts) > 0 {
+		if total := sum(dp.BucketCounts); total != dp.Count {
+			c.add(RuleCounts, fmt.Sprintf("data_points[%d] bucket counts add up to %d, but count is %d", idx, total, dp.Count))
+		}
+	}
+	if dp.Min != nil && dp.Max != nil && dp.GetMin() > dp.GetMax() {
+		c.add(RuleCounts, fmt.Sprintf("data_points[%d] min %g is greater than max %g", idx, dp.GetMin(), dp.GetMax()))
+	}
+}
+
+func (c *checker) exponentialHistogramDataPoint(idx int, dp *protometrics.ExponentialHistogramDataPoint) {
+	c.dataPoint(idx, dp.StartTimeUnixNano, dp.TimeUnixNano)
+
+	if dp.Scale < -10 || dp.Scale > 20 {
+		c.add(RuleBuckets, fmt.Sprintf("data_points[%d] scale %d is outside [-10, 20]", idx, dp.Scale))
+	}
+	total := dp.ZeroCount + sum(dp.GetPositive().GetBucketCounts()) + sum(dp.GetNegative().GetBucketCounts())
+	if total != dp.Count {
+		c.add(RuleCounts, fmt.Sprintf("data_points[%d] zero and bucket counts add up to %d, but count is %d", idx, total, dp.Count))
+	}
+	if dp.Min != nil && dp.Max != nil && dp.GetMin() > dp.GetMax() {
+		c.add(RuleCounts, fmt.Sprintf("data_points[%d] min %g is greater than max %g", idx, dp.GetMin(), dp.GetMax()))
+	}
+	if dp.ZeroThreshold < 0 {
+		c.add(RuleBuckets, fmt.Sprintf("data_points[%d] zero_threshold is negative (%g)", idx, dp.ZeroThreshold))
+	}
+}
+
+func (c *checker) summaryDataPoint(idx int, dp *protometrics.SummaryDataPoint) {
+	c.dataPoint(idx, dp.StartTimeUnixNano, dp.TimeUnixNano)
+
+	if dp.Sum < 0 {
+		c.add(RuleCounts, fmt.Sprintf("data_points[%d] sum is negative (%g)", idx, dp.Sum))
+	}
+	for q, quantile := range dp.QuantileValues {
+		if quantile.Quantile < 0 || quantile.Quantile > 1 {
+			c.add(RuleCounts, fmt.Sprintf("data_points[%d] quantile_values[%d] quantile %g is outside [0, 1]", idx, q, quantile.Quantile))
+		}
+	}
+}
+
+func allZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func sum(counts []uint64) uint64 {
+	var total uint64
+	for _, c := range counts {
+		total += c
+	}
+	return total
+}
@@ -0,0 +1,172 @@
+package analyzer
+
+import (
+	"bytes"
+	"testing"
+
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestValidatorSpan(t *testing.T) {
+	valid := func() *prototrace.Span {
+		return &prototrace.Span{
+			TraceId:           bytes.Repeat([]byte{1}, 16),
+			SpanId:            bytes.Repeat([]byte{2}, 8),
+			Name:              "checkout",
+			StartTimeUnixNano: 100,
+			EndTimeUnixNano:   200,
+		}
+	}
+	tests := []struct {
+		name   string
+		modify func(span *prototrace.Span)
+		want   []string
+	}{
+		{name: "valid", modify: func(*prototrace.Span) {}},
+		{name: "short trace ID", modify: func(s *prototrace.Span) { s.TraceId = s.TraceId[:8] }, want: []string{"trace_id must be 16 bytes, got 8"}},
+		{name: "missing trace ID", modify: func(s *prototrace.Span) { s.TraceId = nil }, want: []string{"trace_id must be 16 bytes, got 0"}},
+		{name: "all-zero trace ID", modify: func(s *prototrace.Span) { s.TraceId = make([]byte, 16) }, want: []string{"trace_id is all zeroes"}},
+		{name: "long span ID", modify: func(s *prototrace.Span) { s.SpanId = make([]byte, 16) }, want: []string{"span_id must be 8 bytes, got 16"}},
+		{name: "all-zero span ID", modify: func(s *prototrace.Span) { s.SpanId = make([]byte, 8) }, want: []string{"span_id is all zeroes"}},
+		{name: "all-zero parent span ID", modify: func(s *prototrace.Span) { s.ParentSpanId = make([]byte, 8) }, want: []string{"parent_span_id is all zeroes"}},
+		{name: "empty name", modify: func(s *prototrace.Span) { s.Name = "" }, want: []string{"name is empty"}},
+		{name: "missing end time", modify: func(s *prototrace.Span) { s.EndTimeUnixNano = 0 }, want: []string{"start_time_unix_nano and end_time_unix_nano are required"}},
+		{name: "end before start", modify: func(s *prototrace.Span) { s.EndTimeUnixNano = 40 }, want: []string{"end time is 60 ns before start time"}},
+		{
+			name: "invalid link",
+			modify: func(s *prototrace.Span) {
+				s.Links = []*prototrace.Span_Link{{TraceId: make([]byte, 16), SpanId: []byte{1}}}
+			},
+			want: []string{"links[0].trace_id is all zeroes", "links[0].span_id must be 8 bytes, got 1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := valid()
+			tt.modify(span)
+			wantMessages(t, NewValidator().Span(span), tt.want)
+		})
+	}
+}
+
+func TestValidatorLogRecord(t *testing.T) {
+	tests := []struct {
+		name   string
+		record *protologs.LogRecord
+		want   []string
+	}{
+		{name: "no trace context", record: &protologs.LogRecord{}},
+		{name: "trace context", record: &protologs.LogRecord{TraceId: bytes.Repeat([]byte{1}, 16), SpanId: bytes.Repeat([]byte{2}, 8)}},
+		{name: "span without trace", record: &protologs.LogRecord{SpanId: bytes.Repeat([]byte{2}, 8)}, want: []string{"span_id is set without trace_id"}},
+		{name: "short trace ID", record: &protologs.LogRecord{TraceId: []byte{1}}, want: []string{"trace_id must be 16 bytes, got 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantMessages(t, NewValidator().LogRecord(tt.record), tt.want)
+		})
+	}
+}
+
+func TestValidatorMetric(t *testing.T) {
+	sum := func(monotonic bool, dp *protometrics.NumberDataPoint) *protometrics.Metric {
+		return &protometrics.Metric{Name: "orders", Data: &protometrics.Metric_Sum{Sum: &protometrics.Sum{
+			IsMonotonic: monotonic,
+			DataPoints:  []*protometrics.NumberDataPoint{dp},
+		}}}
+	}
+	histogram := func(dp *protometrics.HistogramDataPoint) *protometrics.Metric {
+		return &protometrics.Metric{Name: "latency", Data: &protometrics.Metric_Histogram{Histogram: &protometrics.Histogram{
+			DataPoints: []*protometrics.HistogramDataPoint{dp},
+		}}}
+	}
+	minimum, maximum := 5.0, 1.0
+	tests := []struct {
+		name   string
+		metric *protometrics.Metric
+		want   []string
+	}{
+		{name: "valid sum", metric: sum(true, &protometrics.NumberDataPoint{TimeUnixNano: 1, Value: &protometrics.NumberDataPoint_AsInt{AsInt: 3}})},
+		{name: "missing name", metric: &protometrics.Metric{Data: &protometrics.Metric_Gauge{Gauge: &protometrics.Gauge{}}}, want: []string{"name is empty"}},
+		{name: "missing data", metric: &protometrics.Metric{Name: "orders"}, want: []string{"metric has no data (gauge, sum, histogram, exponential_histogram or summary)"}},
+		{
+			name:   "missing time",
+			metric: sum(false, &protometrics.NumberDataPoint{Value: &protometrics.NumberDataPoint_AsInt{AsInt: 3}}),
+			want:   []string{"data_points[0].time_unix_nano is required"},
+		},
+		{
+			name:   "time before start",
+			metric: sum(false, &protometrics.NumberDataPoint{StartTimeUnixNano: 2, TimeUnixNano: 1, Value: &protometrics.NumberDataPoint_AsInt{AsInt: 3}}),
+			want:   []string{"data_points[0] time is before its start time"},
+		},
+		{name: "missing value", metric: sum(false, &protometrics.NumberDataPoint{TimeUnixNano: 1}), want: []string{"data_points[0] has no value"}},
+		{
+			name:   "no recorded value",
+			metric: sum(false, &protometrics.NumberDataPoint{TimeUnixNano: 1, Flags: uint32(protometrics.DataPointFlags_DATA_POINT_FLAGS_NO_RECORDED_VALUE_MASK)}),
+		},
+		{
+			name:   "negative monotonic int",
+			metric: sum(true, &protometrics.NumberDataPoint{TimeUnixNano: 1, Value: &protometrics.NumberDataPoint_AsInt{AsInt: -3}}),
+			want:   []string{"data_points[0] of a monotonic sum is negative (-3)"},
+		},
+		{
+			name:   "negative monotonic double",
+			metric: sum(true, &protometrics.NumberDataPoint{TimeUnixNano: 1, Value: &protometrics.NumberDataPoint_AsDouble{AsDouble: -0.5}}),
+			want:   []string{"data_points[0] of a monotonic sum is negative (-0.5)"},
+		},
+		{
+			name:   "negative non-monotonic sum",
+			metric: sum(false, &protometrics.NumberDataPoint{TimeUnixNano: 1, Value: &protometrics.NumberDataPoint_AsInt{AsInt: -3}}),
+		},
+		{
+			name:   "valid histogram",
+			metric: histogram(&protometrics.HistogramDataPoint{TimeUnixNano: 1, Count: 3, BucketCounts: []uint64{1, 2}, ExplicitBounds: []float64{10}}),
+		},
+		{
+			name:   "bucket and bound mismatch",
+			metric: histogram(&protometrics.HistogramDataPoint{TimeUnixNano: 1, Count: 3, BucketCounts: []uint64{1, 2}, ExplicitBounds: []float64{10, 20}}),
+			want:   []string{"data_points[0] has 2 bucket counts for 2 explicit bounds, want 3"},
+		},
+		{
+			name:   "unordered bounds",
+			metric: histogram(&protometrics.HistogramDataPoint{TimeUnixNano: 1, Count: 3, BucketCounts: []uint64{1, 1, 1}, ExplicitBounds: []float64{20, 10}}),
+			want:   []string{"data_points[0] explicit bounds are not strictly increasing at index 1"},
+		},
+		{
+			name:   "bucket counts and count mismatch",
+			metric: histogram(&protometrics.HistogramDataPoint{TimeUnixNano: 1, Count: 4, BucketCounts: []uint64{1, 2}, ExplicitBounds: []float64{10}}),
+			want:   []string{"data_points[0] bucket counts add up to 3, but count is 4"},
+		},
+		{
+			name:   "min above max",
+			metric: histogram(&protometrics.HistogramDataPoint{TimeUnixNano: 1, Min: &minimum, Max: &maximum}),
+			want:   []string{"data_points[0] min 5 is greater than max 1"},
+		},
+		{
+			name: "exponential histogram counts",
+			metric: &protometrics.Metric{Name: "latency", Data: &protometrics.Metric_ExponentialHistogram{ExponentialHistogram: &protometrics.ExponentialHistogram{
+				DataPoints: []*protometrics.ExponentialHistogramDataPoint{{
+					TimeUnixNano: 1, Scale: 21, Count: 5, ZeroCount: 1,
+					Positive: &protometrics.ExponentialHistogramDataPoint_Buckets{BucketCounts: []uint64{2}},
+				}},
+			}}},
+			want: []string{"data_points[0] scale 21 is outside [-10, 20]", "data_points[0] zero and bucket counts add up to 3, but count is 5"},
+		},
+		{
+			name: "summary quantile out of range",
+			metric: &protometrics.Metric{Name: "latency", Data: &protometrics.Metric_Summary{Summary: &protometrics.Summary{
+				DataPoints: []*protometrics.SummaryDataPoint{{
+					TimeUnixNano:   1,
+					QuantileValues: []*protometrics.SummaryDataPoint_ValueAtQuantile{{Quantile: 0.5}, {Quantile: 99}},
+				}},
+			}}},
+			want: []string{"data_points[0] quantile_values[1] quantile 99 is outside [0, 1]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantMessages(t, NewValidator().Metric(tt.metric), tt.want)
+		})
+	}
+}
@@ -0,0 +1,451 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+var (
+	_ Analyzer = (*Semconv)(nil)
+)
+
+const (
+	RuleDeprecatedAttribute = "semconv/deprecated-attribute"
+	RuleAttributeType       = "semconv/attribute-type"
+	RuleMissingResource     = "semconv/missing-resource-attribute"
+	RuleDeprecatedMetric    = "semconv/deprecated-metric"
+	RuleMetricUnit          = "semconv/metric-unit"
+)
+
+// deprecatedAttributes maps attribute names removed from the semantic conventions to their replacements.
+// An empty replacement means the attribute was removed without one.
+var deprecatedAttributes = map[string]string{
+	"http.method":                           "http.request.method",
+	"http.status_code":                      "http.response.status_code",
+	"http.url":                              "url.full",
+	"http.target":                           "url.path and url.query",
+	"http.scheme":                           "url.scheme",
+	"http.host":                             "server.address",
+	"http.server_name":                      "server.address",
+	"http.user_agent":                       "user_agent.original",
+	"http.client_ip":                        "client.address",
+	"http.flavor":                           "network.protocol.version",
+	"http.request_content_length":           "http.request.body.size",
+	"http.response_content_length":          "http.response.body.size",
+	"net.peer.name":                         "server.address",
+	"net.peer.port":                         "server.port",
+	"net.peer.ip":                           "network.peer.address",
+	"net.host.name":                         "server.address",
+	"net.host.port":                         "server.port",
+	"net.host.ip":                           "network.local.address",
+	"net.sock.peer.addr":                    "network.peer.address",
+	"net.sock.peer.port":                    "network.peer.port",
+	"net.sock.host.addr":                    "network.local.address",
+	"net.sock.host.port":                    "network.local.port",
+	"net.transport":                         "network.transport",
+	"net.protocol.name":                     "network.protocol.name",
+	"net.protocol.version":                  "network.protocol.version",
+	"db.system":                             "db.system.name",
+	"db.statement":                          "db.query.text",
+	"db.operation":                          "db.operation.name",
+	"db.name":                               "db.namespace",
+	"db.sql.table":                          "db.collection.name",
+	"db.cassandra.table":                    "db.collection.name",
+	"db.mongodb.collection":                 "db.collection.name",
+	"db.cosmosdb.container":                 "db.collection.name",
+	"db.redis.database_index":               "db.namespace",
+	"db.connection_string":                  "server.address and server.port",
+	"db.user":                               "",
+	"db.jdbc.driver_classname":              "",
+	"message.type":                          "rpc.message.type",
+	"message.id":                            "rpc.message.id",
+	"message.compressed_size":               "rpc.message.compressed_size",
+	"message.uncompressed_size":             "rpc.message.uncompressed_size",
+	"messaging.operation":                   "messaging.operation.type",
+	"messaging.message_id":                  "messaging.message.id",
+	"messaging.message.payload_size_bytes":  "messaging.message.body.size",
+	"messaging.kafka.destination.partition": "messaging.destination.partition.id",
+	"code.function":                         "code.function.name",
+	"code.filepath":                         "code.file.path",
+	"code.lineno":                           "code.line.number",
+	"code.column":                           "code.column.number",
+	"exception.escaped":                     "",
+	"enduser.id":                            "user.id",
+	"enduser.role":                          "user.roles",
+	"deployment.environment":                "deployment.environment.name",
+	"faas.execution":                        "faas.invocation_id",
+	"otel.library.name":                     "otel.scope.name",
+	"otel.library.version":                  "otel.scope.version",
+	"system.network.state":                  "network.connection.state",
+}
+
+// deprecatedAttributePrefixes maps the prefixes of deprecated templated attributes, such as container.labels.<key>, to
+// their replacement prefixes.
+var deprecatedAttributePrefixes = map[string]string{
+	"container.labels.": "container.label.",
+	"k8s.pod.labels.":   "k8s.pod.label.",
+}
+
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindInt
+	kindDouble
+	kindBool
+	kindStringArray
+)
+
+func (k valueKind) String() string {
+	switch k {
+	case kindInt:
+		return "int"
+	case kindDouble:
+		return "double"
+	case kindBool:
+		return "boolean"
+	case kindStringArray:
+		return "string[]"
+	default:
+		return "string"
+	}
+}
+
+// attributeTypes are the value types required for commonly mis-typed attributes.
+var attributeTypes = map[string]valueKind{
+	"service.name":                      kindString,
+	"service.version":                   kindString,
+	"service.namespace":                 kindString,
+	"service.instance.id":               kindString,
+	"deployment.environment.name":       kindString,
+	"http.request.method":               kindString,
+	"http.response.status_code":         kindInt,
+	"http.request.body.size":            kindInt,
+	"http.response.body.size":           kindInt,
+	"http.request.resend_count":         kindInt,
+	"http.route":                        kindString,
+	"http.status_code":                  kindInt,
+	"url.full":                          kindString,
+	"url.path":                          kindString,
+	"url.scheme":                        kindString,
+	"server.address":                    kindString,
+	"server.port":                       kindInt,
+	"client.address":                    kindString,
+	"client.port":                       kindInt,
+	"network.peer.address":              kindString,
+	"network.peer.port":                 kindInt,
+	"network.local.port":                kindInt,
+	"network.protocol.version":          kindString,
+	"user_agent.original":               kindString,
+	"error.type":                        kindString,
+	"exception.type":                    kindString,
+	"exception.message":                 kindString,
+	"exception.stacktrace":              kindString,
+	"db.system.name":                    kindString,
+	"db.namespace":                      kindString,
+	"db.query.text":                     kindString,
+	"db.operation.name":                 kindString,
+	"db.collection.name":                kindString,
+	"db.response.status_code":           kindString,
+	"db.operation.batch.size":           kindInt,
+	"rpc.system":                        kindString,
+	"rpc.service":                       kindString,
+	"rpc.method":                        kindString,
+	"rpc.grpc.status_code":              kindInt,
+	"messaging.system":                  kindString,
+	"messaging.destination.name":        kindString,
+	"messaging.batch.message_count":     kindInt,
+	"messaging.message.body.size":       kindInt,
+	"code.function.name":                kindString,
+	"code.file.path":                    kindString,
+	"code.line.number":                  kindInt,
+	"code.column.number":                kindInt,
+	"thread.id":                         kindInt,
+	"thread.name":                       kindString,
+	"process.pid":                       kindInt,
+	"process.command_args":              kindStringArray,
+	"process.runtime.name":              kindString,
+	"host.name":                         kindString,
+	"k8s.pod.name":                      kindString,
+	"k8s.namespace.name":                kindString,
+	"cloud.region":                      kindString,
+	"telemetry.sdk.name":                kindString,
+	"telemetry.sdk.language":            kindString,
+	"telemetry.sdk.version":             kindString,
+	"user.id":                           kindString,
+	"session.id":                        kindString,
+	"feature_flag.key":                  kindString,
+	"gen_ai.usage.input_tokens":         kindInt,
+	"gen_ai.usage.output_tokens":        kindInt,
+	"gen_ai.request.max_tokens":         kindInt,
+	"gen_ai.request.temperature":        kindDouble,
+	"http.request.header.content-type":  kindStringArray,
+	"http.response.header.content-type": kindStringArray,
+}
+
+// deprecatedMetrics maps metric names removed from the semantic conventions to their replacements.
+var deprecatedMetrics = map[string]string{
+	"http.server.duration":                "http.server.request.duration",
+	"http.client.duration":                "http.client.request.duration",
+	"http.server.request.size":            "http.server.request.body.size",
+	"http.server.response.size":           "http.server.response.body.size",
+	"http.client.request.size":            "http.client.request.body.size",
+	"http.client.response.size":           "http.client.response.body.size",
+	"db.client.connections.usage":         "db.client.connection.count",
+	"process.runtime.jvm.cpu.utilization": "jvm.cpu.recent_utilization",
+}
+
+// unitReplacements maps common non-UCUM units to the UCUM unit the semantic conventions expect. A lowercase b
+// conventionally means bits, as in Mb, so it's never mapped to bytes.
+var unitReplacements = map[string]string{
+	"seconds":      "s",
+	"second":       "s",
+	"sec":          "s",
+	"secs":         "s",
+	"milliseconds": "ms",
+	"millisecond":  "ms",
+	"millis":       "ms",
+	"msec":         "ms",
+	"microseconds": "us",
+	"nanoseconds":  "ns",
+	"minutes":      "min",
+	"hours":        "h",
+	"bytes":        "By",
+	"byte":         "By",
+	"B":            "By",
+	"KB":           "kBy",
+	"kB":           "kBy",
+	"MB":           "MBy",
+	"GB":           "GBy",
+	"b":            "bit",
+	"kb":           "kbit",
+	"Mb":           "Mbit",
+	"Gb":           "Gbit",
+	"KiB":          "KiBy",
+	"MiB":          "MiBy",
+	"GiB":          "GiBy",
+	"percent":      "1",
+	"percentage":   "1",
+	"ratio":        "1",
+	"count":        "{count}",
+	"requests":     "{request}",
+	"request":      "{request}",
+	"errors":       "{error}",
+	"connections":  "{connection}",
+	"threads":      "{thread}",
+	"messages":     "{message}",
+	"items":        "{item}",
+	"operations":   "{operation}",
+	"celsius":      "Cel",
+}
+
+// Semconv checks telemetry against the OpenTelemetry semantic conventions.
+type Semconv struct{}
+
+func NewSemconv() *Semconv {
+	return &Semconv{}
+}
+
+func (s *Semconv) Resource(res *resourcepb.Resource) []Finding {
+	var attrs []*commonpb.KeyValue
+	if res != nil {
+		attrs = res.Attributes
+	}
+
+	findings := s.attributes("resource", attrs)
+	value, ok := lookup(attrs, "service.name")
+	switch {
+	case !ok:
+		findings = append(findings, Finding{
+			Rule:     RuleMissingResource,
+			Severity: SeverityError,
+			Message:  "required resource attribute service.name is missing",
+			Target:   "resource",
+		})
+	case strings.HasPrefix(value.GetStringValue(), "unknown_service"):
+		findings = append(findings, Finding{
+			Rule:     RuleMissingResource,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("service.name is the SDK default %q, set OTEL_SERVICE_NAME or configure the resource", value.GetStringValue()),
+			Target:   "resource",
+		})
+	}
+	return findings
+}
+
+func (s *Semconv) Span(span *prototrace.Span) []Finding {
+	target := fmt.Sprintf("span %q", span.Name)
+	findings := s.attributes(target, span.Attributes)
+	for _, event := range span.Events {
+		findings = append(findings, s.attributes(fmt.Sprintf("%s event %q", target, event.Name), event.Attributes)...)
+	}
+	return findings
+}
+
+func (s *Semconv) LogRecord(record *protologs.LogRecord) []Finding {
+	target := "log record"
+	if record.EventName != "" {
+		target = fmt.Sprintf("log event %q", record.EventName)
+	}
+	return s.attributes(target, record.Attributes)
+}
+
+func (s *Semconv) Metric(metric *protometrics.Metric) []Finding {
+	target := fmt.Sprintf("metric %q", metric.Name)
+	findings := make([]Finding, 0)
+
+	if replacement, ok := deprecatedMetrics[metric.Name]; ok {
+		findings = append(findings, Finding{
+			Rule:     RuleDeprecatedMetric,
+			Severity: SeverityWarning,
+			Message:  deprecationMessage("metric "+metric.Name, replacement),
+			Target:   target,
+		})
+	}
+
+	findings = append(findings, s.unit(target, metric)...)
+
+	// data points of one metric usually share attribute keys, so only report each key once
+	seen := make(map[string]bool)
+	for _, attrs := range DataPointAttributes(metric) {
+		for _, f := range s.attributes(target, attrs) {
+			if !seen[f.Message] {
+				seen[f.Message] = true
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings
+}
+
+func (s *Semconv) attributes(target string, attrs []*commonpb.KeyValue) []Finding {
+	findings := make([]Finding, 0)
+	for _, kv := range attrs {
+		if replacement, ok := deprecatedAttribute(kv.Key); ok {
+			findings = append(findings, Finding{
+				Rule:     RuleDeprecatedAttribute,
+				Severity: SeverityWarning,
+				Message:  deprecationMessage("attribute "+kv.Key, replacement),
+				Target:   target,
+			})
+		}
+
+		if want, ok := attributeTypes[kv.Key]; ok {
+			if got, ok := kindOf(kv.Value); !ok || got != want {
+				findings = append(findings, Finding{
+					Rule:     RuleAttributeType,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("attribute %s must be %s, got %s", kv.Key, want, describeKind(kv.Value)),
+					Target:   target,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func (s *Semconv) unit(target string, metric *protometrics.Metric) []Finding {
+	unit := metric.Unit
+	if unit == "" {
+		return nil
+	}
+
+	if replacement, ok := unitReplacements[unit]; ok {
+		return []Finding{{
+			Rule:     RuleMetricUnit,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("unit %q is not UCUM, use %q", unit, replacement),
+			Target:   target,
+		}}
+	}
+
+	if strings.ContainsFunc(unit, unicode.IsSpace) {
+		return []Finding{{
+			Rule:     RuleMetricUnit,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("unit %q contains whitespace; use UCUM units, with annotations in braces like {request}", unit),
+			Target:   target,
+		}}
+	}
+
+	if strings.HasSuffix(metric.Name, ".duration") && unit != "s" {
+		return []Finding{{
+			Rule:     RuleMetricUnit,
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("duration metrics should be recorded in seconds (unit \"s\"), got %q", unit),
+			Target:   target,
+		}}
+	}
+	return nil
+}
+
+// deprecatedAttribute returns the replacement for a deprecated attribute, including templated attributes whose prefix
+// is deprecated.
+func deprecatedAttribute(key string) (string, bool) {
+	if replacement, ok := deprecatedAttributes[key]; ok {
+		return replacement, true
+	}
+	for prefix, replacement := range deprecatedAttributePrefixes {
+		if name, found := strings.CutPrefix(key, prefix); found && name != "" {
+			return replacement + name, true
+		}
+	}
+	return "", false
+}
+
+func deprecationMessage(what, replacement string) string {
+	if replacement == "" {
+		return what + " is deprecated and has no replacement"
+	}
+	return fmt.Sprintf("%s is deprecated, use %s", what, replacement)
+}
+
+func kindOf(value *commonpb.AnyValue) (valueKind, bool) {
+	switch v := value.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return kindString, true
+	case *commonpb.AnyValue_IntValue:
+		return kindInt, true
+	case *commonpb.AnyValue_DoubleValue:
+		return kindDouble, true
+	case *commonpb.AnyValue_BoolValue:
+		return kindBool, true
+	case *commonpb.AnyValue_ArrayValue:
+		for _, item := range v.ArrayValue.Values {
+			if _, ok := item.Value.(*commonpb.AnyValue_StringValue); !ok {
+				return 0, false
+			}
+		}
+		return kindStringArray, true
+	default:
+		return 0, false
+	}
+}
+
+func describeKind(value *commonpb.AnyValue) string {
+	switch value.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return "string"
+	case *commonpb.AnyValue_IntValue:
+		return "int"
+	case *commonpb.AnyValue_DoubleValue:
+		return "double"
+	case *commonpb.AnyValue_BoolValue:
+		return "boolean"
+	case *commonpb.AnyValue_ArrayValue:
+		return "array"
+	case *commonpb.AnyValue_KvlistValue:
+		return "map"
+	case *commonpb.AnyValue_BytesValue:
+		return "bytes"
+	default:
+		return "empty value"
+	}
+}
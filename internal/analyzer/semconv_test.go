@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+func TestSemconvDeprecatedAttributes(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want []string
+	}{
+		{name: "exact", key: "http.method", want: []string{"attribute http.method is deprecated, use http.request.method"}},
+		{name: "container label", key: "container.labels.app", want: []string{"attribute container.labels.app is deprecated, use container.label.app"}},
+		{name: "pod label", key: "k8s.pod.labels.app.kubernetes.io/name", want: []string{"attribute k8s.pod.labels.app.kubernetes.io/name is deprecated, use k8s.pod.label.app.kubernetes.io/name"}},
+		{name: "bare prefix", key: "container.labels"},
+		{name: "current label", key: "container.label.app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+				{Key: "service.name", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "checkout"}}},
+				{Key: tt.key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "value"}}},
+			}}
+			wantMessages(t, NewSemconv().Resource(res), tt.want)
+		})
+	}
+}
+
+func TestSemconvMetricUnit(t *testing.T) {
+	tests := []struct {
+		unit string
+		want []string
+	}{
+		{unit: "By"},
+		{unit: "bytes", want: []string{`unit "bytes" is not UCUM, use "By"`}},
+		{unit: "B", want: []string{`unit "B" is not UCUM, use "By"`}},
+		{unit: "b", want: []string{`unit "b" is not UCUM, use "bit"`}},
+		{unit: "KB", want: []string{`unit "KB" is not UCUM, use "kBy"`}},
+		{unit: "kB", want: []string{`unit "kB" is not UCUM, use "kBy"`}},
+		{unit: "kb", want: []string{`unit "kb" is not UCUM, use "kbit"`}},
+		{unit: "Mb", want: []string{`unit "Mb" is not UCUM, use "Mbit"`}},
+		{unit: "MB", want: []string{`unit "MB" is not UCUM, use "MBy"`}},
+		{unit: "requests per second", want: []string{`unit "requests per second" contains whitespace; use UCUM units, with annotations in braces like {request}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			metric := &protometrics.Metric{Name: "transfer.size", Unit: tt.unit}
+			wantMessages(t, NewSemconv().Metric(metric), tt.want)
+		})
+	}
+}
+
+func wantMessages(t *testing.T, findings []Finding, want []string) {
+	t.Helper()
+	got := make([]string, 0, len(findings))
+	for _, f := range findings {
+		got = append(got, f.Message)
+	}
+	if !slices.Equal(got, want) && (len(got) > 0 || len(want) > 0) {
+		t.Errorf("got findings %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,274 @@
+package assembler
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// Assembler buffers spans, and the logs correlated with them, by trace ID across exports and services, and emits each
+// trace as a tree once its window has elapsed since the trace's first span or log arrived.
+type Assembler struct {
+	window time.Duration
+	emit   func(*Trace)
+
+	mu      sync.Mutex
+	pending map[string]*pendingTrace
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type pendingTrace struct {
+	traceID   []byte
+	firstSeen time.Time
+	nodes     []*Node
+	logs      []*Log
+}
+
+// New starts an Assembler that calls emit, from its own goroutine, for each trace whose window has elapsed.
+// Call Close to stop it and emit the traces still buffered.
+func New(window time.Duration, emit func(*Trace)) *Assembler {
+	a := &Assembler{
+		window:  window,
+		emit:    emit,
+		pending: make(map[string]*pendingTrace),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go a.loop()
+	return a
+}
+
+// Add buffers every span in the request under its trace ID.
+func (a *Assembler) Add(req *collectortrace.ExportTraceServiceRequest) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				trace := a.pendingFor(span.TraceId, now)
+				trace.nodes = append(trace.nodes, &Node{
+					Span:     span,
+					Resource: rs.Resource,
+					Scope:    ss.Scope,
+				})
+			}
+		}
+	}
+}
+
+// AddLogs buffers every log record that has a trace ID under that trace, to be attached to its span. Traces with logs
+// but no spans are never emitted.
+func (a *Assembler) AddLogs(req *collectorlogs.ExportLogsServiceRequest) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			for _, record := range sl.LogRecords {
+				if len(record.TraceId) == 0 {
+					continue
+				}
+				trace := a.pendingFor(record.TraceId, now)
+				trace.logs = append(trace.logs, &Log{Record: record, Resource: rl.Resource})
+			}
+		}
+	}
+}
+
+func (a *Assembler) pendingFor(traceID []byte, now time.Time) *pendingTrace {
+	trace, ok := a.pending[string(traceID)]
+	if !ok {
+		trace = &pendingTrace{traceID: traceID, firstSeen: now}
+		a.pending[string(traceID)] = trace
+	}
+	return trace
+}
+
+// Close stops the Assembler and emits all buffered traces, complete or not.
+func (a *Assembler) Close() {
+	close(a.stop)
+	<-a.done
+	a.flush(func(*pendingTrace) bool { return true })
+}
+
+func (a *Assembler) loop() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(max(a.window/10, 10*time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		case now := <-ticker.C:
+			a.flush(func(t *pendingTrace) bool { return now.Sub(t.firstSeen) >= a.window })
+		}
+	}
+}
+
+func (a *Assembler) flush(ready func(*pendingTrace) bool) {
+	a.mu.Lock()
+	flushed := make([]*pendingTrace, 0)
+	for key, trace := range a.pending {
+		if ready(trace) {
+			flushed = append(flushed, trace)
+			delete(a.pending, key)
+		}
+	}
+	a.mu.Unlock()
+
+	slices.SortFunc(flushed, func(x, y *pendingTrace) int {
+		return x.firstSeen.Compare(y.firstSeen)
+	})
+	for _, trace := range flushed {
+		if len(trace.nodes) > 0 {
+			a.emit(build(trace.traceID, trace.nodes, trace.logs))
+		}
+	}
+}
+
+// Trace is a reassembled trace. Roots holds the root spans followed by orphans, each ordered by start time.
+type Trace struct {
+	TraceID []byte
+	Roots   []*Node
+	Spans   int
+	// Logs holds the trace's log records that have no span ID, or whose span didn't arrive, ordered by time.
+	Logs []*Log
+	// Start and End are the earliest set start and latest end time of any span, in Unix nanoseconds.
+	Start uint64
+	End   uint64
+}
+
+type Node struct {
+	Span     *prototrace.Span
+	Resource *resourcepb.Resource
+	Scope    *commonpb.InstrumentationScope
+	Children []*Node
+	// Orphan is set when the span has a parent span ID, but no span with that ID arrived within the window,
+	// or when its ancestors form a cycle.
+	Orphan bool
+	// Logs holds the log records with this span's ID, ordered by time.
+	Logs []*Log
+}
+
+// Log is a log record correlated with a trace by its trace ID.
+type Log struct {
+	Record   *protologs.LogRecord
+	Resource *resourcepb.Resource
+}
+
+// Time is the record's timestamp, or its observed timestamp when unset, in Unix nanoseconds.
+func (l *Log) Time() uint64 {
+	return cmp.Or(l.Record.TimeUnixNano, l.Record.ObservedTimeUnixNano)
+}
+
+// Duration is the time between the earliest span start and the latest span end.
+func (t *Trace) Duration() time.Duration {
+	return time.Duration(t.End - t.Start)
+}
+
+// Walk calls fn for every span in the trace, depth first, with the span's depth in the tree.
+func (t *Trace) Walk(fn func(node *Node, depth int)) {
+	var walk func(nodes []*Node, depth int)
+	walk = func(nodes []*Node, depth int) {
+		for _, node := range nodes {
+			fn(node, depth)
+			walk(node.Children, depth+1)
+		}
+	}
+	walk(t.Roots, 0)
+}
+
+func build(traceID []byte, nodes []*Node, logs []*Log) *Trace {
+	trace := &Trace{TraceID: traceID, Spans: len(nodes)}
+
+	byID := make(map[string]*Node, len(nodes))
+	for _, node := range nodes {
+		if _, exists := byID[string(node.Span.SpanId)]; !exists {
+			byID[string(node.Span.SpanId)] = node
+		}
+
+		// an unset start time would otherwise pull the trace's start back to the epoch
+		if start := node.Span.StartTimeUnixNano; start != 0 && (trace.Start == 0 || start < trace.Start) {
+			trace.Start = start
+		}
+		trace.End = max(trace.End, node.Span.EndTimeUnixNano)
+	}
+	trace.End = max(trace.End, trace.Start)
+
+	var roots, orphans []*Node
+	for _, node := range nodes {
+		if len(node.Span.ParentSpanId) == 0 {
+			roots = append(roots, node)
+			continue
+		}
+		parent, ok := byID[string(node.Span.ParentSpanId)]
+		if !ok || parent == node {
+			node.Orphan = true
+			orphans = append(orphans, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	// spans whose parents form a cycle are unreachable from any root, so detach and show them as orphans
+	reachable := make(map[*Node]bool, len(nodes))
+	var mark func(node *Node)
+	mark = func(node *Node) {
+		reachable[node] = true
+		for _, child := range node.Children {
+			mark(child)
+		}
+	}
+	for _, node := range slices.Concat(roots, orphans) {
+		mark(node)
+	}
+	for _, node := range nodes {
+		if !reachable[node] {
+			parent := byID[string(node.Span.ParentSpanId)]
+			parent.Children = slices.DeleteFunc(parent.Children, func(n *Node) bool { return n == node })
+			node.Orphan = true
+			orphans = append(orphans, node)
+			mark(node)
+		}
+	}
+
+	byStart := func(x, y *Node) int {
+		return cmp.Or(
+			cmp.Compare(x.Span.StartTimeUnixNano, y.Span.StartTimeUnixNano),
+			bytes.Compare(x.Span.SpanId, y.Span.SpanId),
+		)
+	}
+	for _, node := range nodes {
+		slices.SortStableFunc(node.Children, byStart)
+	}
+	slices.SortStableFunc(roots, byStart)
+	slices.SortStableFunc(orphans, byStart)
+
+	slices.SortStableFunc(logs, func(x, y *Log) int { return cmp.Compare(x.Time(), y.Time()) })
+	for _, log := range logs {
+		if node, ok := byID[string(log.Record.SpanId)]; ok && len(log.Record.SpanId) > 0 {
+			node.Logs = append(node.Logs, log)
+		} else {
+			trace.Logs = append(trace.Logs, log)
+		}
+	}
+
+	trace.Roots = append(roots, orphans...)
+	return trace
+}
@@ -0,0 +1,57 @@
+package assembler
+
+import (
+	"testing"
+
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestBuildIgnoresUnsetStartTimes(t *testing.T) {
+	traceID := make([]byte, 16)
+	tests := []struct {
+		name  string
+		spans []*prototrace.Span
+		start uint64
+		end   uint64
+	}{
+		{
+			name: "unset start first",
+			spans: []*prototrace.Span{
+				{TraceId: traceID, SpanId: []byte{1}, EndTimeUnixNano: 1500},
+				{TraceId: traceID, SpanId: []byte{2}, StartTimeUnixNano: 1000, EndTimeUnixNano: 2000},
+			},
+			start: 1000,
+			end:   2000,
+		},
+		{
+			name: "unset start last",
+			spans: []*prototrace.Span{
+				{TraceId: traceID, SpanId: []byte{2}, StartTimeUnixNano: 1000, EndTimeUnixNano: 2000},
+				{TraceId: traceID, SpanId: []byte{1}, EndTimeUnixNano: 1500},
+			},
+			start: 1000,
+			end:   2000,
+		},
+		{
+			name: "earliest start wins",
+			spans: []*prototrace.Span{
+				{TraceId: traceID, SpanId: []byte{2}, StartTimeUnixNano: 1200, EndTimeUnixNano: 2000},
+				{TraceId: traceID, SpanId: []byte{1}, StartTimeUnixNano: 1100, EndTimeUnixNano: 1500},
+			},
+			start: 1100,
+			end:   2000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := make([]*Node, 0, len(tt.spans))
+			for _, span := range tt.spans {
+				nodes = append(nodes, &Node{Span: span})
+			}
+			trace := build(traceID, nodes, nil)
+			if trace.Start != tt.start || trace.End != tt.end {
+				t.Errorf("got start %d, end %d, want start %d, end %d", trace.Start, trace.End, tt.start, tt.end)
+			}
+		})
+	}
+}
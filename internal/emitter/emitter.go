@@ -3,30 +3,38 @@ package emitter
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	relay "github.com/jimschubert/otel-relay/inspector"
 	"github.com/jimschubert/otel-relay/proto/inspector"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/proto"
 )
 
-type Emitter interface {
-	EmitTrace(data proto.Message) error
-	EmitMetric(data proto.Message) error
-	EmitLog(data proto.Message) error
-}
+var (
+	_ relay.Emitter = (*grpcEmitter)(nil)
+)
 
 type grpcEmitter struct {
 	socketPath string
-	conn       *grpc.ClientConn
-	client     inspector.InspectorServiceClient
+
+	// mu guards the lazily created connection, since proxies emit from their request goroutines
+	mu     sync.Mutex
+	conn   *grpc.ClientConn
+	client inspector.InspectorServiceClient
 }
 
-func NewGrpcEmitter(socketPath string) Emitter {
+func NewGrpcEmitter(socketPath string) relay.Emitter {
 	return &grpcEmitter{socketPath: socketPath}
 }
-func (e *grpcEmitter) EmitTrace(data proto.Message) error {
-	if err := e.connect(); err != nil {
+
+func (e *grpcEmitter) EmitTrace(ctx context.Context, data *collectortrace.ExportTraceServiceRequest) error {
+	client, err := e.connect()
+	if err != nil {
 		return err
 	}
 
@@ -36,16 +44,18 @@ func (e *grpcEmitter) EmitTrace(data proto.Message) error {
 	}
 
 	event := &inspector.TelemetryEvent{
-		Data: bytes,
-		Type: inspector.TelemetryType_TELEMETRY_TYPE_TRACE,
+		Data:       bytes,
+		Type:       inspector.TelemetryType_TELEMETRY_TYPE_TRACE,
+		Violations: violations(ctx),
 	}
 
-	_, err = e.client.Emit(context.Background(), event)
+	_, err = client.Emit(ctx, event)
 	return err
 }
 
-func (e *grpcEmitter) EmitMetric(data proto.Message) error {
-	if err := e.connect(); err != nil {
+func (e *grpcEmitter) EmitMetric(ctx context.Context, data *collectormetrics.ExportMetricsServiceRequest) error {
+	client, err := e.connect()
+	if err != nil {
 		return err
 	}
 
@@ -55,16 +65,18 @@ func (e *grpcEmitter) EmitMetric(data proto.Message) error {
 	}
 
 	event := &inspector.TelemetryEvent{
-		Data: bytes,
-		Type: inspector.TelemetryType_TELEMETRY_TYPE_METRIC,
+		Data:       bytes,
+		Type:       inspector.TelemetryType_TELEMETRY_TYPE_METRIC,
+		Violations: violations(ctx),
 	}
 
-	_, err = e.client.Emit(context.Background(), event)
+	_, err = client.Emit(ctx, event)
 	return err
 }
 
-func (e *grpcEmitter) EmitLog(data proto.Message) error {
-	if err := e.connect(); err != nil {
+func (e *grpcEmitter) EmitLog(ctx context.Context, data *collectorlogs.ExportLogsServiceRequest) error {
+	client, err := e.connect()
+	if err != nil {
 		return err
 	}
 
@@ -74,17 +86,21 @@ func (e *grpcEmitter) EmitLog(data proto.Message) error {
 	}
 
 	event := &inspector.TelemetryEvent{
-		Data: bytes,
-		Type: inspector.TelemetryType_TELEMETRY_TYPE_LOG,
+		Data:       bytes,
+		Type:       inspector.TelemetryType_TELEMETRY_TYPE_LOG,
+		Violations: violations(ctx),
 	}
 
-	_, err = e.client.Emit(context.Background(), event)
+	_, err = client.Emit(ctx, event)
 	return err
 }
 
-func (e *grpcEmitter) connect() error {
+func (e *grpcEmitter) connect() (inspector.InspectorServiceClient, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	if e.client != nil {
-		return nil
+		return e.client, nil
 	}
 
 	conn, err := grpc.NewClient(
@@ -92,10 +108,29 @@ func (e *grpcEmitter) connect() error {
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to connect to gRPC daemon: %w", err)
+		return nil, fmt.Errorf("failed to connect to gRPC daemon: %w", err)
 	}
 
 	e.conn = conn
 	e.client = inspector.NewInspectorServiceClient(conn)
-	return nil
+	return e.client, nil
+}
+
+// violations converts any violations the inspector flagged on this request for the event.
+func violations(ctx context.Context) []*inspector.Violation {
+	flagged := relay.ViolationsFromContext(ctx)
+	if len(flagged) == 0 {
+		return nil
+	}
+
+	result := make([]*inspector.Violation, 0, len(flagged))
+	for _, v := range flagged {
+		result = append(result, &inspector.Violation{
+			Rule:    v.Rule,
+			Message: v.Message,
+			Target:  v.Target,
+			Service: v.Service,
+		})
+	}
+	return result
 }
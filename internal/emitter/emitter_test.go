@@ -0,0 +1,38 @@
+package emitter
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jimschubert/otel-relay/proto/inspector"
+)
+
+func TestConnectConcurrently(t *testing.T) {
+	e := &grpcEmitter{socketPath: filepath.Join(t.TempDir(), "relay.sock")}
+	t.Cleanup(func() {
+		if e.conn != nil {
+			_ = e.conn.Close()
+		}
+	})
+
+	// run with -race: every goroutine must share the one lazily created connection
+	clients := make([]inspector.InspectorServiceClient, 16)
+	var wg sync.WaitGroup
+	for idx := range clients {
+		wg.Go(func() {
+			client, err := e.connect()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			clients[idx] = client
+		})
+	}
+	wg.Wait()
+
+	for idx, client := range clients {
+		if client == nil || client != clients[0] {
+			t.Fatalf("client %d differs from the first", idx)
+		}
+	}
+}
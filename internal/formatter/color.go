@@ -0,0 +1,86 @@
+package formatter
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+	ansiCyan   = "\x1b[36m"
+
+	// minValueWidth keeps truncated values readable on very narrow terminals, at the cost of wrapping.
+	minValueWidth = 10
+)
+
+// WithColor highlights headers, errors, log severities and attribute keys using ANSI escape codes.
+func WithColor(color bool) TreeOption {
+	return func(f *TreeFormatter) {
+		f.color = color
+	}
+}
+
+// WithWidth truncates attribute values and log bodies so lines fit within width columns, unless verbose.
+// A width of 0 disables truncation.
+func WithWidth(width int) TreeOption {
+	return func(f *TreeFormatter) {
+		f.width = width
+	}
+}
+
+// paint wraps s in the given ANSI codes when color is enabled.
+func (f *TreeFormatter) paint(s string, codes ...string) string {
+	return paint(f.color, s, codes...)
+}
+
+func paint(enabled bool, s string, codes ...string) string {
+	if !enabled || len(codes) == 0 || s == "" {
+		return s
+	}
+	return strings.Join(codes, "") + s + ansiReset
+}
+
+func severityColor(severity protologs.SeverityNumber) []string {
+	switch {
+	case severity >= protologs.SeverityNumber_SEVERITY_NUMBER_FATAL:
+		return []string{ansiBold, ansiRed}
+	case severity >= protologs.SeverityNumber_SEVERITY_NUMBER_ERROR:
+		return []string{ansiRed}
+	case severity >= protologs.SeverityNumber_SEVERITY_NUMBER_WARN:
+		return []string{ansiYellow}
+	case severity >= protologs.SeverityNumber_SEVERITY_NUMBER_INFO:
+		return []string{ansiGreen}
+	case severity >= protologs.SeverityNumber_SEVERITY_NUMBER_DEBUG:
+		return []string{ansiBlue}
+	case severity > protologs.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED:
+		return []string{ansiDim}
+	default:
+		return nil
+	}
+}
+
+// fit truncates value so that a line starting with lead fits within the configured width.
+// Values are never truncated in verbose mode or when no width is set.
+func (f *TreeFormatter) fit(lead, value string) string {
+	if f.verbose || f.width <= 0 {
+		return value
+	}
+	return truncate(value, max(f.width-utf8.RuneCountInString(lead), minValueWidth))
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
+}
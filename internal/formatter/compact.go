@@ -0,0 +1,141 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+var (
+	_ Formatter = (*CompactFormatter)(nil)
+)
+
+const (
+	compactTimeFmt      = "15:04:05.000"
+	compactServiceWidth = 16
+	compactNameWidth    = 32
+	compactBodyWidth    = 120
+)
+
+// DefaultColumns are the attributes a CompactFormatter shows when none are configured.
+var DefaultColumns = []string{
+	"http.request.method",
+	"http.method",
+	"http.route",
+	"http.response.status_code",
+	"http.status_code",
+	"rpc.method",
+	"db.system.name",
+	"db.system",
+	"error.type",
+}
+
+// CompactFormatter prints one line per span, log record and data point, like tailing a log file.
+type CompactFormatter struct {
+	columns []string
+	color   bool
+}
+
+// NewCompactFormatter shows the given attributes, looked up on the item and then its resource, as key=value columns
+// at the end of each line. Nil columns means DefaultColumns.
+func NewCompactFormatter(columns []string, color bool) *CompactFormatter {
+	if columns == nil {
+		columns = DefaultColumns
+	}
+	return &CompactFormatter{columns: columns, color: color}
+}
+
+func (c *CompactFormatter) FormatTrace(req *collectortrace.ExportTraceServiceRequest) string {
+	return c.render(traceRecords(req))
+}
+
+func (c *CompactFormatter) FormatMetric(req *collectormetrics.ExportMetricsServiceRequest) string {
+	return c.render(metricRecords(req))
+}
+
+func (c *CompactFormatter) FormatLog(req *collectorlogs.ExportLogsServiceRequest) string {
+	return c.render(logRecords(req))
+}
+
+func (c *CompactFormatter) render(records []*Record) string {
+	var buf strings.Builder
+	for _, record := range records {
+		timestamp := "--:--:--.---"
+		if !record.Time.IsZero() {
+			timestamp = record.Time.Format(compactTimeFmt)
+		}
+		fmt.Fprintf(&buf, "%s  %s  %s  ",
+			paint(c.color, timestamp, ansiDim),
+			pad(truncate(record.ServiceName(), compactServiceWidth), compactServiceWidth),
+			paint(c.color, pad(strings.ToUpper(record.Signal), 6), ansiCyan),
+		)
+
+		switch record.Signal {
+		case "span":
+			fmt.Fprintf(&buf, "%s  %s", pad(truncate(record.Name, compactNameWidth), compactNameWidth), record.Duration)
+			if record.Status == "ERROR" {
+				status := "ERROR"
+				if record.StatusMessage != "" {
+					status += ": " + record.StatusMessage
+				}
+				fmt.Fprintf(&buf, "  %s", paint(c.color, status, ansiRed))
+			}
+		case "log":
+			severity := pad(record.Severity, 5)
+			fmt.Fprintf(&buf, "%s  %s",
+				paint(c.color, severity, severityColor(protologs.SeverityNumber(record.SeverityNumber))...),
+				truncate(escapeControl(record.Body), compactBodyWidth),
+			)
+		case "metric":
+			fmt.Fprintf(&buf, "%s  %s", pad(truncate(record.Name, compactNameWidth), compactNameWidth), metricValue(record))
+		}
+
+		for _, key := range c.columns {
+			value := attrLookup(record.Attributes, key)
+			if value == "" {
+				value = attrLookup(record.Resource, key)
+			}
+			if value == "" {
+				continue
+			}
+			fmt.Fprintf(&buf, "  %s=%s", paint(c.color, key, ansiDim), columnValue(value))
+		}
+		fmt.Fprintf(&buf, "\n")
+	}
+	return buf.String()
+}
+
+func metricValue(record *Record) string {
+	var value string
+	switch record.Type {
+	case "Gauge", "Sum":
+		value = formatFloat(record.Value)
+	default:
+		value = fmt.Sprintf("count=%d sum=%s", record.Count, formatFloat(record.Sum))
+	}
+	if record.Unit != "" && record.Unit != "1" {
+		value += " " + record.Unit
+	}
+	return value
+}
+
+func columnValue(value any) string {
+	s := fmt.Sprint(value)
+	if strings.ContainsAny(s, " \t\n\"") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// pad right-pads s with spaces to width runes.
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -0,0 +1,82 @@
+package formatter
+
+import (
+	"sync"
+
+	"github.com/jimschubert/otel-relay/internal/analyzer"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+// maxCorrelatedSpans bounds the recently seen spans that log records are matched against.
+const maxCorrelatedSpans = 10000
+
+type seenSpan struct {
+	name    string
+	service string
+}
+
+// spanIndex remembers the most recently seen spans by trace and span ID, evicting the oldest first.
+type spanIndex struct {
+	mu    sync.Mutex
+	spans map[string]seenSpan
+	order []string
+	next  int
+}
+
+func newSpanIndex() *spanIndex {
+	return &spanIndex{spans: make(map[string]seenSpan)}
+}
+
+func (i *spanIndex) add(req *collectortrace.ExportTraceServiceRequest) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, rs := range req.ResourceSpans {
+		service := analyzer.ServiceName(rs.Resource)
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				key := string(span.TraceId) + string(span.SpanId)
+				if _, ok := i.spans[key]; !ok {
+					if len(i.order) < maxCorrelatedSpans {
+						i.order = append(i.order, key)
+					} else {
+						delete(i.spans, i.order[i.next])
+						i.order[i.next] = key
+						i.next = (i.next + 1) % len(i.order)
+					}
+				}
+				i.spans[key] = seenSpan{name: span.Name, service: service}
+			}
+		}
+	}
+}
+
+func (i *spanIndex) lookup(traceID, spanID []byte) (seenSpan, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	span, ok := i.spans[string(traceID)+string(spanID)]
+	return span, ok
+}
+
+// ObserveTrace remembers the request's spans, so log records with their IDs show which span they belong to, without
+// rendering the request. FormatTrace does this too.
+func (f *TreeFormatter) ObserveTrace(req *collectortrace.ExportTraceServiceRequest) {
+	f.spans.add(req)
+}
+
+// correlatedSpan describes the span a log record belongs to, when that span has been seen.
+func (f *TreeFormatter) correlatedSpan(traceID, spanID []byte) string {
+	if len(traceID) == 0 || len(spanID) == 0 {
+		return ""
+	}
+	span, ok := f.spans.lookup(traceID, spanID)
+	if !ok {
+		return ""
+	}
+	described := f.paint(span.name, ansiBold)
+	if span.service != "" {
+		described += " [" + span.service + "]"
+	}
+	return described
+}
@@ -0,0 +1,106 @@
+package formatter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+var (
+	_ Formatter = (*CSVFormatter)(nil)
+)
+
+// CSVFormatter collects a row per span, log record and data point, and writes them as one CSV table on Flush.
+// The header is only known once every row has been seen, since each attribute gets its own column.
+type CSVFormatter struct {
+	mu   sync.Mutex
+	rows []map[string]string
+}
+
+func NewCSVFormatter() *CSVFormatter {
+	return &CSVFormatter{}
+}
+
+func (c *CSVFormatter) FormatTrace(req *collectortrace.ExportTraceServiceRequest) string {
+	c.add(traceRecords(req))
+	return ""
+}
+
+func (c *CSVFormatter) FormatMetric(req *collectormetrics.ExportMetricsServiceRequest) string {
+	c.add(metricRecords(req))
+	return ""
+}
+
+func (c *CSVFormatter) FormatLog(req *collectorlogs.ExportLogsServiceRequest) string {
+	c.add(logRecords(req))
+	return ""
+}
+
+func (c *CSVFormatter) add(records []*Record) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, record := range records {
+		row := make(map[string]string)
+		for _, field := range flattenRecord(record) {
+			row[field.key] = field.value
+		}
+		c.rows = append(c.rows, row)
+	}
+}
+
+// Flush writes the collected rows to w, with a header of flatColumns, then resource attributes, then scope attributes,
+// then attributes, and forgets them.
+func (c *CSVFormatter) Flush(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var resource, scope, attributes []string
+	for _, row := range c.rows {
+		for key := range row {
+			if seen[key] || slices.Contains(flatColumns, key) {
+				continue
+			}
+			seen[key] = true
+			switch {
+			case strings.HasPrefix(key, "resource."):
+				resource = append(resource, key)
+			case strings.HasPrefix(key, "scope."):
+				scope = append(scope, key)
+			default:
+				attributes = append(attributes, key)
+			}
+		}
+	}
+	slices.Sort(resource)
+	slices.Sort(scope)
+	slices.Sort(attributes)
+	header := slices.Concat(flatColumns, resource, scope, attributes)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	record := make([]string, len(header))
+	for _, row := range c.rows {
+		for idx, key := range header {
+			record[idx] = row[key]
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	c.rows = nil
+	return nil
+}
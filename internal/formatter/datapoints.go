@@ -0,0 +1,283 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+const (
+	maxDataPoints  = 5
+	bucketBarWidth = 30
+	timestampFmt   = "2006-01-02 15:04:05.000"
+)
+
+// buildDataPoints lists the metric's data points, at most 5 unless verbose. build writes the rest of each data
+// point's first line, then its details using the given prefix.
+func (f *TreeFormatter) buildDataPoints(buf io.Writer, count int, build func(prefix string, idx int)) {
+	fmt.Fprintf(buf, "│  └─ Data points: %d\n", count)
+
+	shown := count
+	if !f.verbose && count > maxDataPoints {
+		shown = maxDataPoints
+	}
+	for idx := range shown {
+		connector, prefix := "├─", "│     │  "
+		if idx == count-1 {
+			connector, prefix = "└─", "│        "
+		}
+		fmt.Fprintf(buf, "│     %s [%d] ", connector, idx)
+		build(prefix, idx)
+	}
+	if shown < count {
+		fmt.Fprintf(buf, "│     └─ ... (%d more data points)\n", count-shown)
+	}
+}
+
+// buildDataPointDetails writes a data point's timestamps and attributes. last is set when nothing follows them.
+func (f *TreeFormatter) buildDataPointDetails(buf io.Writer, prefix string, start, ts uint64, attrs []*commonpb.KeyValue, last bool) {
+	connector := "├─"
+	if last && len(attrs) == 0 {
+		connector = "└─"
+	}
+	fmt.Fprintf(buf, "%s%s Time: %s", prefix, connector, formatTimestamp(ts))
+	if start != 0 {
+		fmt.Fprintf(buf, " (start: %s)", formatTimestamp(start))
+	}
+	fmt.Fprintf(buf, "\n")
+
+	if len(attrs) == 0 {
+		return
+	}
+	if last {
+		fmt.Fprintf(buf, "%s└─ Attributes:\n", prefix)
+		f.buildAttr(buf, prefix+"   ", attrs)
+	} else {
+		fmt.Fprintf(buf, "%s├─ Attributes:\n", prefix)
+		f.buildAttr(buf, prefix+"│  ", attrs)
+	}
+}
+
+func (f *TreeFormatter) buildNumberDataPoints(buf io.Writer, points []*protometrics.NumberDataPoint) {
+	f.buildDataPoints(buf, len(points), func(prefix string, idx int) {
+		dp := points[idx]
+		fmt.Fprintf(buf, "%s\n", numberValue(dp))
+		f.buildDataPointDetails(buf, prefix, dp.StartTimeUnixNano, dp.TimeUnixNano, dp.Attributes, true)
+	})
+}
+
+func (f *TreeFormatter) buildHistogramDataPoints(buf io.Writer, points []*protometrics.HistogramDataPoint) {
+	f.buildDataPoints(buf, len(points), func(prefix string, idx int) {
+		dp := points[idx]
+		fmt.Fprintf(buf, "count=%d", dp.Count)
+		if dp.Sum != nil {
+			fmt.Fprintf(buf, " sum=%s", formatFloat(dp.GetSum()))
+		}
+		if dp.Min != nil {
+			fmt.Fprintf(buf, " min=%s", formatFloat(dp.GetMin()))
+		}
+		if dp.Max != nil {
+			fmt.Fprintf(buf, " max=%s", formatFloat(dp.GetMax()))
+		}
+		fmt.Fprintf(buf, "\n")
+
+		hasBuckets := len(dp.BucketCounts) > 0
+		f.buildDataPointDetails(buf, prefix, dp.StartTimeUnixNano, dp.TimeUnixNano, dp.Attributes, !hasBuckets)
+		if hasBuckets {
+			fmt.Fprintf(buf, "%s└─ Buckets:\n", prefix)
+			buildBuckets(buf, prefix+"   ", histogramBucketLabels(dp.ExplicitBounds, len(dp.BucketCounts)), dp.BucketCounts)
+		}
+	})
+}
+
+func (f *TreeFormatter) buildExponentialHistogramDataPoints(buf io.Writer, points []*protometrics.ExponentialHistogramDataPoint) {
+	f.buildDataPoints(buf, len(points), func(prefix string, idx int) {
+		dp := points[idx]
+		fmt.Fprintf(buf, "count=%d", dp.Count)
+		if dp.Sum != nil {
+			fmt.Fprintf(buf, " sum=%s", formatFloat(dp.GetSum()))
+		}
+		if dp.Min != nil {
+			fmt.Fprintf(buf, " min=%s", formatFloat(dp.GetMin()))
+		}
+		if dp.Max != nil {
+			fmt.Fprintf(buf, " max=%s", formatFloat(dp.GetMax()))
+		}
+		fmt.Fprintf(buf, "\n")
+
+		fmt.Fprintf(buf, "%s├─ Scale: %d (base %s)\n", prefix, dp.Scale, formatBound(exponentialBase(dp.Scale)))
+		fmt.Fprintf(buf, "%s├─ Zero count: %d", prefix, dp.ZeroCount)
+		if dp.ZeroThreshold != 0 {
+			fmt.Fprintf(buf, " (threshold %s)", formatFloat(dp.ZeroThreshold))
+		}
+		fmt.Fprintf(buf, "\n")
+
+		labels, counts := exponentialBuckets(dp, f.verbose)
+		hasBuckets := len(counts) > 0
+		f.buildDataPointDetails(buf, prefix, dp.StartTimeUnixNano, dp.TimeUnixNano, dp.Attributes, !hasBuckets)
+		if hasBuckets {
+			fmt.Fprintf(buf, "%s└─ Buckets:", prefix)
+			if total := len(dp.GetPositive().GetBucketCounts()) + len(dp.GetNegative().GetBucketCounts()) + 1; total > len(counts) {
+				fmt.Fprintf(buf, " (%d empty not shown)", total-len(counts))
+			}
+			fmt.Fprintf(buf, "\n")
+			buildBuckets(buf, prefix+"   ", labels, counts)
+		}
+	})
+}
+
+func (f *TreeFormatter) buildSummaryDataPoints(buf io.Writer, points []*protometrics.SummaryDataPoint) {
+	f.buildDataPoints(buf, len(points), func(prefix string, idx int) {
+		dp := points[idx]
+		fmt.Fprintf(buf, "count=%d sum=%s\n", dp.Count, formatFloat(dp.Sum))
+
+		hasQuantiles := len(dp.QuantileValues) > 0
+		f.buildDataPointDetails(buf, prefix, dp.StartTimeUnixNano, dp.TimeUnixNano, dp.Attributes, !hasQuantiles)
+		if hasQuantiles {
+			fmt.Fprintf(buf, "%s└─ Quantiles:\n", prefix)
+			for q, quantile := range dp.QuantileValues {
+				connector := "├─"
+				if q == len(dp.QuantileValues)-1 {
+					connector = "└─"
+				}
+				fmt.Fprintf(buf, "%s   %s %s: %s\n", prefix, connector, quantileLabel(quantile.Quantile), formatFloat(quantile.Value))
+			}
+		}
+	})
+}
+
+// buildBuckets charts bucket counts as bars scaled to the largest count, with labels and counts aligned.
+func buildBuckets(buf io.Writer, prefix string, labels []string, counts []uint64) {
+	labelWidth, countWidth := 0, 0
+	var largest uint64
+	for idx, count := range counts {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(labels[idx]))
+		countWidth = max(countWidth, len(strconv.FormatUint(count, 10)))
+		largest = max(largest, count)
+	}
+
+	for idx, count := range counts {
+		connector := "├─"
+		if idx == len(counts)-1 {
+			connector = "└─"
+		}
+		bar := 0
+		if largest > 0 {
+			bar = int(math.Ceil(float64(count) / float64(largest) * bucketBarWidth))
+		}
+		fmt.Fprintf(buf, "%s%s %s%s  %*d %s\n",
+			prefix,
+			connector,
+			labels[idx],
+			strings.Repeat(" ", labelWidth-utf8.RuneCountInString(labels[idx])),
+			countWidth,
+			count,
+			strings.Repeat("█", bar),
+		)
+	}
+}
+
+// histogramBucketLabels describes each explicit bucket as a range. Buckets are upper-inclusive, and the last is unbounded.
+func histogramBucketLabels(bounds []float64, buckets int) []string {
+	labels := make([]string, buckets)
+	for idx := range buckets {
+		lower, upper := "-∞", "+∞"
+		if idx > 0 && idx-1 < len(bounds) {
+			lower = formatFloat(bounds[idx-1])
+		}
+		if idx < len(bounds) {
+			upper = formatFloat(bounds[idx])
+			labels[idx] = fmt.Sprintf("(%s, %s]", lower, upper)
+		} else {
+			labels[idx] = fmt.Sprintf("(%s, %s)", lower, upper)
+		}
+	}
+	return labels
+}
+
+// exponentialBuckets decodes the negative, zero and positive buckets, in ascending order, into ranges of real values.
+// Positive bucket index i covers (base^i, base^(i+1)], and negative buckets mirror it. Empty buckets are skipped unless all is set.
+func exponentialBuckets(dp *protometrics.ExponentialHistogramDataPoint, all bool) ([]string, []uint64) {
+	labels := make([]string, 0)
+	counts := make([]uint64, 0)
+	add := func(label string, count uint64) {
+		if all || count > 0 {
+			labels = append(labels, label)
+			counts = append(counts, count)
+		}
+	}
+
+	negative := dp.GetNegative()
+	for k := len(negative.GetBucketCounts()) - 1; k >= 0; k-- {
+		index := negative.Offset + int32(k)
+		add(fmt.Sprintf("[-%s, -%s)",
+			formatBound(exponentialBound(dp.Scale, index+1)),
+			formatBound(exponentialBound(dp.Scale, index)),
+		), negative.BucketCounts[k])
+	}
+
+	if dp.ZeroThreshold != 0 {
+		add(fmt.Sprintf("[-%s, %s]", formatFloat(dp.ZeroThreshold), formatFloat(dp.ZeroThreshold)), dp.ZeroCount)
+	} else {
+		add("0", dp.ZeroCount)
+	}
+
+	positive := dp.GetPositive()
+	for k, count := range positive.GetBucketCounts() {
+		index := positive.Offset + int32(k)
+		add(fmt.Sprintf("(%s, %s]",
+			formatBound(exponentialBound(dp.Scale, index)),
+			formatBound(exponentialBound(dp.Scale, index+1)),
+		), count)
+	}
+	return labels, counts
+}
+
+// exponentialBase is the ratio between adjacent bucket boundaries at the given scale, 2^(2^-scale).
+func exponentialBase(scale int32) float64 {
+	return math.Exp2(math.Exp2(-float64(scale)))
+}
+
+// exponentialBound is the lower boundary of the bucket with the given index, base^index.
+func exponentialBound(scale, index int32) float64 {
+	return math.Exp2(float64(index) * math.Exp2(-float64(scale)))
+}
+
+// formatBound rounds computed bucket boundaries, which are rarely exact, to 6 significant digits.
+func formatBound(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+func numberValue(dp *protometrics.NumberDataPoint) string {
+	switch v := dp.Value.(type) {
+	case *protometrics.NumberDataPoint_AsInt:
+		return strconv.FormatInt(v.AsInt, 10)
+	case *protometrics.NumberDataPoint_AsDouble:
+		return formatFloat(v.AsDouble)
+	default:
+		return "<no recorded value>"
+	}
+}
+
+// quantileLabel names a quantile as a percentile, rounded to 4 decimals so 0.07 is p7 rather than p7.000000000000001.
+func quantileLabel(q float64) string {
+	return "p" + formatFloat(math.Round(q*100*1e4)/1e4)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func formatTimestamp(nanos uint64) string {
+	if nanos == 0 {
+		return "<unset>"
+	}
+	return time.Unix(0, int64(nanos)).Format(timestampFmt)
+}
@@ -0,0 +1,26 @@
+package formatter
+
+import "testing"
+
+func TestQuantileLabel(t *testing.T) {
+	tests := []struct {
+		quantile float64
+		want     string
+	}{
+		{quantile: 0, want: "p0"},
+		{quantile: 0.07, want: "p7"},
+		{quantile: 0.29, want: "p29"},
+		{quantile: 0.5, want: "p50"},
+		{quantile: 0.57, want: "p57"},
+		{quantile: 0.999, want: "p99.9"},
+		{quantile: 0.9999, want: "p99.99"},
+		{quantile: 1, want: "p100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := quantileLabel(tt.quantile); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+const (
+	maxEvents          = 5
+	maxStacktraceLines = 10
+)
+
+// buildEvents lists the span's events, at most 5 unless verbose, each with its time relative to the span's start.
+func (f *TreeFormatter) buildEvents(buf io.Writer, prefix string, span *prototrace.Span) {
+	shown := len(span.Events)
+	if !f.verbose && shown > maxEvents {
+		shown = maxEvents
+	}
+
+	for idx, event := range span.Events[:shown] {
+		connector, childPrefix := "├─", prefix+"│  "
+		if idx == len(span.Events)-1 {
+			connector, childPrefix = "└─", prefix+"   "
+		}
+		fmt.Fprintf(buf, "%s%s [%d] %s %s", prefix, connector, idx, relativeTime(span.StartTimeUnixNano, event.TimeUnixNano), event.Name)
+		if event.DroppedAttributesCount > 0 {
+			fmt.Fprintf(buf, " (%d attributes dropped)", event.DroppedAttributesCount)
+		}
+		fmt.Fprintf(buf, "\n")
+		f.buildEventAttributes(buf, childPrefix, event.Attributes)
+	}
+	if shown < len(span.Events) {
+		fmt.Fprintf(buf, "%s└─ ... (%d more events)\n", prefix, len(span.Events)-shown)
+	}
+}
+
+// buildEventAttributes lists an event's attributes, printing an exception.stacktrace last and one frame per line.
+func (f *TreeFormatter) buildEventAttributes(buf io.Writer, prefix string, attrs []*commonpb.KeyValue) {
+	var stacktrace *commonpb.KeyValue
+	others := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		if kv.Key == "exception.stacktrace" && kv.Value.GetStringValue() != "" {
+			stacktrace = kv
+		} else {
+			others = append(others, kv)
+		}
+	}
+
+	if stacktrace == nil {
+		f.buildAttr(buf, prefix, attrs)
+		return
+	}
+
+	// the exception's type and message are what matter most, so they're never elided like other attributes
+	for _, kv := range others {
+		f.buildKeyValue(buf, prefix, "├─", kv)
+	}
+	fmt.Fprintf(buf, "%s└─ %s:\n", prefix, f.paint(stacktrace.Key, ansiDim))
+
+	lines := strings.Split(strings.TrimRight(stacktrace.Value.GetStringValue(), "\n"), "\n")
+	shown := len(lines)
+	if !f.verbose && shown > maxStacktraceLines {
+		shown = maxStacktraceLines
+	}
+	for _, line := range lines[:shown] {
+		line = strings.ReplaceAll(strings.TrimRight(line, "\r"), "\t", "    ")
+		fmt.Fprintf(buf, "%s     %s\n", prefix, f.fit(prefix+"     ", line))
+	}
+	if shown < len(lines) {
+		fmt.Fprintf(buf, "%s     ... (%d more lines)\n", prefix, len(lines)-shown)
+	}
+}
+
+// buildLinks lists the span's links with the linked span context and the link's attributes.
+func (f *TreeFormatter) buildLinks(buf io.Writer, prefix string, span *prototrace.Span) {
+	for idx, link := range span.Links {
+		connector, childPrefix := "├─", prefix+"│  "
+		if idx == len(span.Links)-1 {
+			connector, childPrefix = "└─", prefix+"   "
+		}
+		fmt.Fprintf(buf, "%s%s [%d] TraceID: %x, SpanID: %x", prefix, connector, idx, link.TraceId, link.SpanId)
+		if bytes.Equal(link.TraceId, span.TraceId) {
+			fmt.Fprintf(buf, " (same trace)")
+		}
+		fmt.Fprintf(buf, "\n")
+
+		details := make([]string, 0, 2)
+		if link.TraceState != "" {
+			details = append(details, "TraceState: "+link.TraceState)
+		}
+		if link.DroppedAttributesCount > 0 {
+			details = append(details, fmt.Sprintf("Dropped attributes: %d", link.DroppedAttributesCount))
+		}
+		for idx, detail := range details {
+			if idx == len(details)-1 && len(link.Attributes) == 0 {
+				fmt.Fprintf(buf, "%s└─ %s\n", childPrefix, detail)
+			} else {
+				fmt.Fprintf(buf, "%s├─ %s\n", childPrefix, detail)
+			}
+		}
+		if len(link.Attributes) > 0 {
+			fmt.Fprintf(buf, "%s└─ Attributes:\n", childPrefix)
+			f.buildAttr(buf, childPrefix+"   ", link.Attributes)
+		}
+	}
+}
+
+// relativeTime formats an event time as an offset from the span's start, e.g. "+1.5ms".
+func relativeTime(start, ts uint64) string {
+	if ts == 0 {
+		return "<unset>"
+	}
+	offset := time.Duration(int64(ts) - int64(start))
+	if offset < 0 {
+		return offset.String()
+	}
+	return "+" + offset.String()
+}
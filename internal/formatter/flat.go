@@ -0,0 +1,169 @@
+package formatter
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// flatColumns are the fields every flattened row can have, in output order. Resource attributes follow as
+// resource.<key>, scope attributes as scope.<key>, then the item's own attributes under their own keys.
+var flatColumns = []string{
+	"time",
+	"signal",
+	"scope.name",
+	"scope.version",
+	"name",
+	"trace_id",
+	"span_id",
+	"parent_span_id",
+	"kind",
+	"duration_ms",
+	"status",
+	"status_message",
+	"events",
+	"links",
+	"severity",
+	"severity_number",
+	"body",
+	"type",
+	"unit",
+	"description",
+	"value",
+	"count",
+	"sum",
+}
+
+// flatField is one key and value of a flattened record.
+type flatField struct {
+	key   string
+	value string
+}
+
+// flattenRecord converts a record to key/value pairs in flatColumns order, followed by its sorted resource, scope and
+// own attributes. Empty fields are omitted. An attribute whose key clashes with one of flatColumns is prefixed with
+// "attributes.", and a scope attribute that clashes, such as "name", with "scope.attributes.".
+func flattenRecord(record *Record) []flatField {
+	values := map[string]string{
+		"time":          flatTime(record.Time),
+		"signal":        record.Signal,
+		"scope.name":    record.Scope,
+		"scope.version": record.ScopeVersion,
+		"name":          record.Name,
+		"trace_id":      record.TraceID,
+		"span_id":       record.SpanID,
+	}
+	switch record.Signal {
+	case "span":
+		values["parent_span_id"] = record.ParentSpanID
+		values["kind"] = record.Kind
+		values["duration_ms"] = formatFloat(float64(record.Duration) / float64(time.Millisecond))
+		values["status"] = record.Status
+		values["status_message"] = record.StatusMessage
+		values["events"] = strconv.Itoa(record.Events)
+		values["links"] = strconv.Itoa(record.Links)
+	case "log":
+		values["severity"] = record.Severity
+		if record.SeverityNumber != 0 {
+			values["severity_number"] = strconv.Itoa(int(record.SeverityNumber))
+		}
+		values["body"] = record.Body
+	case "metric":
+		values["type"] = record.Type
+		values["unit"] = record.Unit
+		values["description"] = record.Description
+		switch record.Type {
+		case "Gauge", "Sum":
+			values["value"] = formatFloat(record.Value)
+		default:
+			values["count"] = strconv.FormatUint(record.Count, 10)
+			values["sum"] = formatFloat(record.Sum)
+		}
+	}
+
+	fields := make([]flatField, 0, len(values))
+	for _, key := range flatColumns {
+		if values[key] != "" {
+			fields = append(fields, flatField{key, values[key]})
+		}
+	}
+	fields = append(fields, flattenAttributes("resource.", record.Resource)...)
+	for _, field := range flattenAttributes("scope.", record.ScopeAttributes) {
+		if slices.Contains(flatColumns, field.key) {
+			field.key = "scope.attributes." + strings.TrimPrefix(field.key, "scope.")
+		}
+		fields = append(fields, field)
+	}
+	for _, field := range flattenAttributes("", record.Attributes) {
+		if slices.Contains(flatColumns, field.key) {
+			field.key = "attributes." + field.key
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// flattenAttributes joins the keys of a map created by nestAttributes back into dotted keys, sorted.
+// Key-value list attributes are flattened the same way, so {"db": {"rows": 1}} becomes db.rows=1.
+func flattenAttributes(prefix string, attrs map[string]any) []flatField {
+	fields := make([]flatField, 0, len(attrs))
+	var walk func(prefix string, m map[string]any)
+	walk = func(prefix string, m map[string]any) {
+		for key, value := range m {
+			if nested, ok := value.(map[string]any); ok {
+				walk(prefix+key+".", nested)
+				continue
+			}
+			// nestAttributes marks a key that's also a prefix of other keys with a trailing dot
+			fields = append(fields, flatField{prefix + strings.TrimSuffix(key, "."), plainText(value)})
+		}
+	}
+	walk(prefix, attrs)
+	slices.SortFunc(fields, func(a, b flatField) int {
+		return strings.Compare(a.key, b.key)
+	})
+	return fields
+}
+
+// plainText renders a value created by anyValue on one line, like valueText does for the original attribute.
+func plainText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return formatFloat(v)
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, plainText(item))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		values := make([]string, 0, len(v))
+		for _, key := range keys {
+			values = append(values, key+"="+plainText(v[key]))
+		}
+		return "{" + strings.Join(values, ", ") + "}"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func flatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
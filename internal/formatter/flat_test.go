@@ -0,0 +1,34 @@
+package formatter
+
+import (
+	"slices"
+	"testing"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestFlattenRecordScopeAttributes(t *testing.T) {
+	attr := func(key string) *commonpb.KeyValue {
+		return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "v"}}}
+	}
+	records := traceRecords(&collectortrace.ExportTraceServiceRequest{ResourceSpans: []*prototrace.ResourceSpans{{
+		Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{attr("service.name")}},
+		ScopeSpans: []*prototrace.ScopeSpans{{
+			Scope: &commonpb.InstrumentationScope{Name: "db", Attributes: []*commonpb.KeyValue{attr("pool"), attr("name")}},
+			Spans: []*prototrace.Span{{Name: "query", Attributes: []*commonpb.KeyValue{attr("db.system")}}},
+		}},
+	}}})
+
+	var keys []string
+	for _, field := range flattenRecord(records[0]) {
+		keys = append(keys, field.key)
+	}
+	want := []string{"signal", "scope.name", "name", "kind", "duration_ms", "status", "events", "links",
+		"resource.service.name", "scope.attributes.name", "scope.pool", "db.system"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("got keys %q, want %q", keys, want)
+	}
+}
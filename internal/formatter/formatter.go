@@ -2,20 +2,27 @@ package formatter
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/jimschubert/otel-relay/internal/analyzer"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
 	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
 	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
 	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
-	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
-	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
-	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 )
 
+// maxBytesPreview is how many bytes of a bytes value are shown unless verbose.
+const maxBytesPreview = 32
+
 type Formatter interface {
 	FormatTrace(*collectortrace.ExportTraceServiceRequest) string
 	FormatMetric(*collectormetrics.ExportMetricsServiceRequest) string
@@ -23,11 +30,36 @@ type Formatter interface {
 }
 
 type TreeFormatter struct {
-	verbose bool
+	verbose   bool
+	color     bool
+	width     int
+	analyzers []analyzer.Analyzer
+	traces    []analyzer.TraceAnalyzer
+	spans     *spanIndex
 }
 
-func NewTreeFormatter(verbose bool) *TreeFormatter {
-	return &TreeFormatter{verbose: verbose}
+type TreeOption func(*TreeFormatter)
+
+// WithAnalyzers shows the findings of each analyzer inline, under the resource, span, metric or log they concern.
+func WithAnalyzers(analyzers ...analyzer.Analyzer) TreeOption {
+	return func(f *TreeFormatter) {
+		f.analyzers = append(f.analyzers, analyzers...)
+	}
+}
+
+// WithTraceAnalyzers shows the findings of each trace analyzer in waterfalls, under the span they concern.
+func WithTraceAnalyzers(analyzers ...analyzer.TraceAnalyzer) TreeOption {
+	return func(f *TreeFormatter) {
+		f.traces = append(f.traces, analyzers...)
+	}
+}
+
+func NewTreeFormatter(verbose bool, opts ...TreeOption) *TreeFormatter {
+	f := &TreeFormatter{verbose: verbose, spans: newSpanIndex()}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 func (f *TreeFormatter) SetVerbose(verbose bool) {
@@ -35,13 +67,16 @@ func (f *TreeFormatter) SetVerbose(verbose bool) {
 }
 
 func (f *TreeFormatter) FormatTrace(req *collectortrace.ExportTraceServiceRequest) string {
+	f.ObserveTrace(req)
+
 	var buf bytes.Buffer
 	for _, resourceSpan := range req.ResourceSpans {
 		resource := resourceSpan.Resource
 
-		fmt.Fprintf(&buf, "\n📊 TRACE\n")
+		fmt.Fprintf(&buf, "\n%s\n", f.paint("📊 TRACE", ansiBold, ansiCyan))
 		fmt.Fprintf(&buf, "├─ Resource:\n")
 		f.buildAttr(&buf, "│  ", resource.Attributes)
+		f.buildFindings(&buf, "│  ", f.analyze(func(a analyzer.Analyzer) []analyzer.Finding { return a.Resource(resource) }))
 
 		for _, scopeSpan := range resourceSpan.ScopeSpans {
 			scope := scopeSpan.Scope
@@ -67,9 +102,10 @@ func (f *TreeFormatter) FormatMetric(req *collectormetrics.ExportMetricsServiceR
 	for _, resourceMetric := range req.ResourceMetrics {
 		resource := resourceMetric.Resource
 
-		fmt.Fprintf(&buf, "\n📈 METRIC\n")
+		fmt.Fprintf(&buf, "\n%s\n", f.paint("📈 METRIC", ansiBold, ansiCyan))
 		fmt.Fprintf(&buf, "├─ Resource:\n")
 		f.buildAttr(&buf, "│  ", resource.Attributes)
+		f.buildFindings(&buf, "│  ", f.analyze(func(a analyzer.Analyzer) []analyzer.Finding { return a.Resource(resource) }))
 
 		for _, scopeMetric := range resourceMetric.ScopeMetrics {
 			scope := scopeMetric.Scope
@@ -95,9 +131,10 @@ func (f *TreeFormatter) FormatLog(req *collectorlogs.ExportLogsServiceRequest) s
 	for _, resourceLog := range req.ResourceLogs {
 		resource := resourceLog.Resource
 
-		fmt.Fprintf(&buf, "\n📝 LOG\n")
+		fmt.Fprintf(&buf, "\n%s\n", f.paint("📝 LOG", ansiBold, ansiCyan))
 		fmt.Fprintf(&buf, "├─ Resource:\n")
 		f.buildAttr(&buf, "│  ", resource.Attributes)
+		f.buildFindings(&buf, "│  ", f.analyze(func(a analyzer.Analyzer) []analyzer.Finding { return a.Resource(resource) }))
 
 		for _, scopeLog := range resourceLog.ScopeLogs {
 			scope := scopeLog.Scope
@@ -120,7 +157,7 @@ func (f *TreeFormatter) FormatLog(req *collectorlogs.ExportLogsServiceRequest) s
 
 func (f *TreeFormatter) buildSpan(buf *bytes.Buffer, span *prototrace.Span) {
 	fmt.Fprintf(buf, "│\n")
-	fmt.Fprintf(buf, "├─ 🔗 Span: %s\n", span.Name)
+	fmt.Fprintf(buf, "├─ 🔗 Span: %s\n", f.paint(span.Name, ansiBold))
 	fmt.Fprintf(buf, "│  ├─ TraceID: %x\n", span.TraceId)
 	fmt.Fprintf(buf, "│  ├─ SpanID: %x\n", span.SpanId)
 	if len(span.ParentSpanId) > 0 {
@@ -134,11 +171,17 @@ func (f *TreeFormatter) buildSpan(buf *bytes.Buffer, span *prototrace.Span) {
 	fmt.Fprintf(buf, "│  ├─ Duration: %v\n", duration)
 
 	if span.Status != nil {
-		fmt.Fprintf(buf, "│  ├─ Status: %s", span.Status.Code.String())
+		status := span.Status.Code.String()
 		if span.Status.Message != "" {
-			fmt.Fprintf(buf, " - %s", span.Status.Message)
+			status += " - " + span.Status.Message
 		}
-		fmt.Fprintf(buf, "\n")
+		switch span.Status.Code {
+		case prototrace.Status_STATUS_CODE_ERROR:
+			status = f.paint(status, ansiRed)
+		case prototrace.Status_STATUS_CODE_OK:
+			status = f.paint(status, ansiGreen)
+		}
+		fmt.Fprintf(buf, "│  ├─ Status: %s\n", status)
 	}
 
 	if len(span.Attributes) > 0 {
@@ -146,56 +189,72 @@ func (f *TreeFormatter) buildSpan(buf *bytes.Buffer, span *prototrace.Span) {
 		f.buildAttr(buf, "│  │  ", span.Attributes)
 	}
 
+	f.buildFindings(buf, "│  ", f.analyze(func(a analyzer.Analyzer) []analyzer.Finding { return a.Span(span) }))
+
 	if len(span.Events) > 0 {
-		fmt.Fprintf(buf, "│  ├─ Events: %d\n", len(span.Events))
-		if f.verbose {
-			for idx, event := range span.Events {
-				fmt.Fprintf(buf, "│  │  ├─ [%d] %s\n", idx, event.Name)
-			}
+		if len(span.Links) > 0 {
+			fmt.Fprintf(buf, "│  ├─ Events: %d\n", len(span.Events))
+			f.buildEvents(buf, "│  │  ", span)
+		} else {
+			fmt.Fprintf(buf, "│  └─ Events: %d\n", len(span.Events))
+			f.buildEvents(buf, "│     ", span)
 		}
 	}
 
 	if len(span.Links) > 0 {
 		fmt.Fprintf(buf, "│  └─ Links: %d\n", len(span.Links))
+		f.buildLinks(buf, "│     ", span)
 	}
 }
 
 func (f *TreeFormatter) buildMetric(buf io.Writer, metric *protometrics.Metric) {
 	fmt.Fprintf(buf, "│\n")
-	fmt.Fprintf(buf, "├─ 📊 Metric: %s\n", metric.Name)
+	fmt.Fprintf(buf, "├─ 📊 Metric: %s\n", f.paint(metric.Name, ansiBold))
 	if metric.Description != "" {
 		fmt.Fprintf(buf, "│  ├─ Description: %s\n", metric.Description)
 	}
 	if metric.Unit != "" {
 		fmt.Fprintf(buf, "│  ├─ Unit: %s\n", metric.Unit)
 	}
+	f.buildFindings(buf, "│  ", f.analyze(func(a analyzer.Analyzer) []analyzer.Finding { return a.Metric(metric) }))
 
 	switch data := metric.Data.(type) {
 	case *protometrics.Metric_Gauge:
 		fmt.Fprintf(buf, "│  ├─ Type: Gauge\n")
-		fmt.Fprintf(buf, "│  └─ Data points: %d\n", len(data.Gauge.DataPoints))
+		f.buildNumberDataPoints(buf, data.Gauge.DataPoints)
 	case *protometrics.Metric_Sum:
 		fmt.Fprintf(buf, "│  ├─ Type: Sum\n")
 		fmt.Fprintf(buf, "│  ├─ Aggregation: %s\n", data.Sum.AggregationTemporality.String())
-		fmt.Fprintf(buf, "│  └─ Data points: %d\n", len(data.Sum.DataPoints))
+		fmt.Fprintf(buf, "│  ├─ Monotonic: %t\n", data.Sum.IsMonotonic)
+		f.buildNumberDataPoints(buf, data.Sum.DataPoints)
 	case *protometrics.Metric_Histogram:
 		fmt.Fprintf(buf, "│  ├─ Type: Histogram\n")
-		fmt.Fprintf(buf, "│  └─ Data points: %d\n", len(data.Histogram.DataPoints))
+		fmt.Fprintf(buf, "│  ├─ Aggregation: %s\n", data.Histogram.AggregationTemporality.String())
+		f.buildHistogramDataPoints(buf, data.Histogram.DataPoints)
+	case *protometrics.Metric_ExponentialHistogram:
+		fmt.Fprintf(buf, "│  ├─ Type: ExponentialHistogram\n")
+		fmt.Fprintf(buf, "│  ├─ Aggregation: %s\n", data.ExponentialHistogram.AggregationTemporality.String())
+		f.buildExponentialHistogramDataPoints(buf, data.ExponentialHistogram.DataPoints)
 	case *protometrics.Metric_Summary:
 		fmt.Fprintf(buf, "│  ├─ Type: Summary\n")
-		fmt.Fprintf(buf, "│  └─ Data points: %d\n", len(data.Summary.DataPoints))
+		f.buildSummaryDataPoints(buf, data.Summary.DataPoints)
 	}
 }
 
 func (f *TreeFormatter) buildLogRecord(buf *bytes.Buffer, log *protologs.LogRecord) {
 	fmt.Fprintf(buf, "│\n")
 	fmt.Fprintf(buf, "├─ 📄 Log\n")
-	fmt.Fprintf(buf, "│  ├─ Severity: %s\n", log.SeverityText)
+	fmt.Fprintf(buf, "│  ├─ Severity: %s\n", f.paint(log.SeverityText, severityColor(log.SeverityNumber)...))
 
-	if log.Body != nil {
+	if kvlist := log.Body.GetKvlistValue(); kvlist != nil && len(kvlist.Values) > 0 {
+		fmt.Fprintf(buf, "│  ├─ Body:\n")
+		f.buildAttr(buf, "│  │  ", kvlist.Values)
+	} else if log.Body != nil {
 		body := f.attributeValueToString(log.Body)
-		if !f.verbose && len(body) > 100 {
-			body = body[:97] + "..."
+		if f.width > 0 {
+			body = f.fit("│  ├─ Body: ", body)
+		} else if !f.verbose {
+			body = truncate(body, 100)
 		}
 		fmt.Fprintf(buf, "│  ├─ Body: %s\n", body)
 	}
@@ -204,20 +263,58 @@ func (f *TreeFormatter) buildLogRecord(buf *bytes.Buffer, log *protologs.LogReco
 		fmt.Fprintf(buf, "│  ├─ TraceID: %x\n", log.TraceId)
 	}
 	if len(log.SpanId) > 0 {
-		fmt.Fprintf(buf, "│  ├─ SpanID: %x\n", log.SpanId)
+		fmt.Fprintf(buf, "│  ├─ SpanID: %x", log.SpanId)
+		if span := f.correlatedSpan(log.TraceId, log.SpanId); span != "" {
+			fmt.Fprintf(buf, " → %s", span)
+		}
+		fmt.Fprintf(buf, "\n")
 	}
 
 	if len(log.Attributes) > 0 && f.verbose {
 		fmt.Fprintf(buf, "│  ├─ Attributes:\n")
 		f.buildAttr(buf, "│  │  ", log.Attributes)
 	}
+
+	f.buildFindings(buf, "│  ", f.analyze(func(a analyzer.Analyzer) []analyzer.Finding { return a.LogRecord(log) }))
+}
+
+func (f *TreeFormatter) analyze(fn func(analyzer.Analyzer) []analyzer.Finding) []analyzer.Finding {
+	findings := make([]analyzer.Finding, 0)
+	for _, a := range f.analyzers {
+		findings = append(findings, fn(a)...)
+	}
+	return findings
+}
+
+func (f *TreeFormatter) buildFindings(buf io.Writer, prefix string, findings []analyzer.Finding) {
+	for _, finding := range findings {
+		message := finding.Message
+		switch finding.Severity {
+		case analyzer.SeverityError:
+			message = f.paint(message, ansiRed)
+		case analyzer.SeverityWarning:
+			message = f.paint(message, ansiYellow)
+		}
+		fmt.Fprintf(buf, "%s├─ %s %s (%s)\n", prefix, SeverityIcon(finding.Severity), message, finding.Rule)
+	}
+}
+
+// SeverityIcon is the marker used to display findings of the given severity.
+func SeverityIcon(severity analyzer.Severity) string {
+	switch severity {
+	case analyzer.SeverityError:
+		return "❌"
+	case analyzer.SeverityWarning:
+		return "⚠️"
+	default:
+		return "ℹ️"
+	}
 }
 
 func (f *TreeFormatter) buildAttr(buf io.Writer, prefix string, attrs []*commonpb.KeyValue) {
 	if !f.verbose && len(attrs) > 5 {
 		for idx := range 5 {
-			kv := attrs[idx]
-			fmt.Fprintf(buf, "%s├─ %s: %s\n", prefix, kv.Key, f.attributeValueToString(kv.Value))
+			f.buildKeyValue(buf, prefix, "├─", attrs[idx])
 		}
 		fmt.Fprintf(buf, "%s└─ ... (%d more attributes)\n", prefix, len(attrs)-5)
 	} else {
@@ -226,11 +323,28 @@ func (f *TreeFormatter) buildAttr(buf io.Writer, prefix string, attrs []*commonp
 			if idx == len(attrs)-1 {
 				connector = "└─"
 			}
-			fmt.Fprintf(buf, "%s%s %s: %s\n", prefix, connector, kv.Key, f.attributeValueToString(kv.Value))
+			f.buildKeyValue(buf, prefix, connector, kv)
+		}
+	}
+}
+
+// buildKeyValue writes an attribute on one line, or a key-value list value as a nested branch of its own attributes.
+func (f *TreeFormatter) buildKeyValue(buf io.Writer, prefix, connector string, kv *commonpb.KeyValue) {
+	key := escapeControl(kv.Key)
+	if kvlist := kv.Value.GetKvlistValue(); kvlist != nil && len(kvlist.Values) > 0 {
+		fmt.Fprintf(buf, "%s%s %s:\n", prefix, connector, f.paint(key, ansiDim))
+		childPrefix := prefix + "│  "
+		if connector == "└─" {
+			childPrefix = prefix + "   "
 		}
+		f.buildAttr(buf, childPrefix, kvlist.Values)
+		return
 	}
+	value := f.fit(fmt.Sprintf("%s%s %s: ", prefix, connector, key), f.attributeValueToString(kv.Value))
+	fmt.Fprintf(buf, "%s%s %s: %s\n", prefix, connector, f.paint(key, ansiDim), value)
 }
 
+// attributeValueToString renders a value on one line. Key-value lists nested in arrays are shown inline as {k=v, ...}.
 func (f *TreeFormatter) attributeValueToString(value *commonpb.AnyValue) string {
 	if value == nil {
 		return "<nil>"
@@ -238,13 +352,13 @@ func (f *TreeFormatter) attributeValueToString(value *commonpb.AnyValue) string
 
 	switch v := value.Value.(type) {
 	case *commonpb.AnyValue_StringValue:
-		return v.StringValue
+		return escapeControl(v.StringValue)
 	case *commonpb.AnyValue_BoolValue:
 		return fmt.Sprintf("%t", v.BoolValue)
 	case *commonpb.AnyValue_IntValue:
 		return fmt.Sprintf("%d", v.IntValue)
 	case *commonpb.AnyValue_DoubleValue:
-		return fmt.Sprintf("%f", v.DoubleValue)
+		return formatFloat(v.DoubleValue)
 	case *commonpb.AnyValue_ArrayValue:
 		values := make([]string, len(v.ArrayValue.Values))
 		for idx, val := range v.ArrayValue.Values {
@@ -252,10 +366,41 @@ func (f *TreeFormatter) attributeValueToString(value *commonpb.AnyValue) string
 		}
 		return "[" + strings.Join(values, ", ") + "]"
 	case *commonpb.AnyValue_KvlistValue:
-		return fmt.Sprintf("{%d keys}", len(v.KvlistValue.Values))
+		values := make([]string, len(v.KvlistValue.Values))
+		for idx, kv := range v.KvlistValue.Values {
+			values[idx] = escapeControl(kv.Key) + "=" + f.attributeValueToString(kv.Value)
+		}
+		return "{" + strings.Join(values, ", ") + "}"
 	case *commonpb.AnyValue_BytesValue:
-		return fmt.Sprintf("<bytes: %d>", len(v.BytesValue))
+		return f.bytesPreview(v.BytesValue)
 	default:
 		return "<unknown>"
 	}
 }
+
+// bytesPreview shows bytes as hex and base64, limited to the first 32 bytes unless verbose.
+func (f *TreeFormatter) bytesPreview(b []byte) string {
+	shown, more := b, ""
+	if !f.verbose && len(b) > maxBytesPreview {
+		shown, more = b[:maxBytesPreview], "…"
+	}
+	return fmt.Sprintf("0x%x%s (%d bytes, base64 %s%s)", shown, more, len(b), base64.StdEncoding.EncodeToString(shown), more)
+}
+
+// escapeControl replaces control characters, such as newlines and ANSI escapes, with Go escape sequences, so
+// values can't break the tree or change the terminal's state.
+func escapeControl(s string) string {
+	if !strings.ContainsFunc(s, unicode.IsControl) {
+		return s
+	}
+	var buf strings.Builder
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			quoted := strconv.QuoteRune(r)
+			buf.WriteString(quoted[1 : len(quoted)-1])
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
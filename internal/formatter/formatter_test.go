@@ -0,0 +1,47 @@
+package formatter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+)
+
+func TestBuildKeyValueEscapesKeys(t *testing.T) {
+	value := &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "paid"}}
+	tests := []struct {
+		name  string
+		kv    *commonpb.KeyValue
+		lines int
+		want  string
+	}{
+		{
+			name:  "scalar",
+			kv:    &commonpb.KeyValue{Key: "order\n\x1b[31mstatus", Value: value},
+			lines: 1,
+			want:  `└─ order\n\x1b[31mstatus: paid`,
+		},
+		{
+			name: "key-value list",
+			kv: &commonpb.KeyValue{Key: "order\r\nstatus", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{
+				KvlistValue: &commonpb.KeyValueList{Values: []*commonpb.KeyValue{{Key: "state", Value: value}}},
+			}}},
+			lines: 2,
+			want:  `└─ order\r\nstatus:`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewTreeFormatter(false).buildKeyValue(&buf, "", "└─", tt.kv)
+			got := buf.String()
+			if lines := strings.Count(got, "\n"); lines != tt.lines || strings.ContainsAny(got, "\r\x1b") {
+				t.Errorf("got %q, want %d lines without control characters", got, tt.lines)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
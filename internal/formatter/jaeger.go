@@ -0,0 +1,200 @@
+package formatter
+
+import (
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// jaegerFile is the response of Jaeger's /api/traces, which is also what the Jaeger UI accepts as a JSON file.
+type jaegerFile struct {
+	Data []*jaegerTrace `json:"data"`
+}
+
+type jaegerTrace struct {
+	TraceID   string                   `json:"traceID"`
+	Spans     []jaegerSpan             `json:"spans"`
+	Processes map[string]jaegerProcess `json:"processes"`
+
+	// processIDs maps a process's service name and tags to its ID in Processes
+	processIDs map[string]string
+}
+
+type jaegerSpan struct {
+	TraceID       string            `json:"traceID"`
+	SpanID        string            `json:"spanID"`
+	OperationName string            `json:"operationName"`
+	References    []jaegerReference `json:"references"`
+	Flags         uint32            `json:"flags"`
+	StartTime     uint64            `json:"startTime"`
+	Duration      uint64            `json:"duration"`
+	Tags          []jaegerTag       `json:"tags"`
+	Logs          []jaegerLog       `json:"logs"`
+	ProcessID     string            `json:"processID"`
+}
+
+type jaegerReference struct {
+	RefType string `json:"refType"`
+	TraceID string `json:"traceID"`
+	SpanID  string `json:"spanID"`
+}
+
+type jaegerTag struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+type jaegerLog struct {
+	Timestamp uint64      `json:"timestamp"`
+	Fields    []jaegerTag `json:"fields"`
+}
+
+type jaegerProcess struct {
+	ServiceName string      `json:"serviceName"`
+	Tags        []jaegerTag `json:"tags"`
+}
+
+// jaegerDocument groups spans by trace, in the order each trace was first seen. Times are in microseconds, as Jaeger
+// expects, and span kind, scope and status become the tags Jaeger's own OTLP receiver would set.
+func jaegerDocument(reqs []*collectortrace.ExportTraceServiceRequest) *jaegerFile {
+	file := &jaegerFile{Data: make([]*jaegerTrace, 0)}
+	traces := make(map[string]*jaegerTrace)
+	for _, req := range reqs {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					traceID := hex.EncodeToString(span.TraceId)
+					trace, ok := traces[traceID]
+					if !ok {
+						trace = &jaegerTrace{
+							TraceID:    traceID,
+							Spans:      make([]jaegerSpan, 0),
+							Processes:  make(map[string]jaegerProcess),
+							processIDs: make(map[string]string),
+						}
+						traces[traceID] = trace
+						file.Data = append(file.Data, trace)
+					}
+					trace.Spans = append(trace.Spans, jaegerSpanOf(span, ss.Scope, trace.process(rs.Resource)))
+				}
+			}
+		}
+	}
+	return file
+}
+
+// process returns the ID of the resource's process in the trace, adding it on first use.
+func (t *jaegerTrace) process(res *resourcepb.Resource) string {
+	process := jaegerProcess{ServiceName: "unknown_service", Tags: make([]jaegerTag, 0)}
+	var key strings.Builder
+	for _, kv := range res.GetAttributes() {
+		if kv.Key == "service.name" {
+			process.ServiceName = kv.Value.GetStringValue()
+			continue
+		}
+		process.Tags = append(process.Tags, jaegerTagOf(kv.Key, kv.Value))
+		key.WriteString(kv.Key + "=" + valueText(kv.Value) + "\n")
+	}
+
+	key.WriteString(process.ServiceName)
+	if id, ok := t.processIDs[key.String()]; ok {
+		return id
+	}
+	id := "p" + strconv.Itoa(len(t.Processes)+1)
+	t.processIDs[key.String()] = id
+	t.Processes[id] = process
+	return id
+}
+
+func jaegerSpanOf(span *prototrace.Span, scope *commonpb.InstrumentationScope, processID string) jaegerSpan {
+	traceID := hex.EncodeToString(span.TraceId)
+	out := jaegerSpan{
+		TraceID:       traceID,
+		SpanID:        hex.EncodeToString(span.SpanId),
+		OperationName: span.Name,
+		References:    make([]jaegerReference, 0, len(span.Links)+1),
+		Flags:         span.Flags & uint32(prototrace.SpanFlags_SPAN_FLAGS_TRACE_FLAGS_MASK),
+		StartTime:     span.StartTimeUnixNano / 1000,
+		Tags:          make([]jaegerTag, 0, len(span.Attributes)),
+		Logs:          make([]jaegerLog, 0, len(span.Events)),
+		ProcessID:     processID,
+	}
+	if span.EndTimeUnixNano > span.StartTimeUnixNano {
+		out.Duration = (span.EndTimeUnixNano - span.StartTimeUnixNano) / 1000
+	}
+
+	if len(span.ParentSpanId) > 0 {
+		out.References = append(out.References, jaegerReference{RefType: "CHILD_OF", TraceID: traceID, SpanID: hex.EncodeToString(span.ParentSpanId)})
+	}
+	for _, link := range span.Links {
+		out.References = append(out.References, jaegerReference{RefType: "FOLLOWS_FROM", TraceID: hex.EncodeToString(link.TraceId), SpanID: hex.EncodeToString(link.SpanId)})
+	}
+
+	for _, kv := range span.Attributes {
+		out.Tags = append(out.Tags, jaegerTagOf(kv.Key, kv.Value))
+	}
+	if kind := spanKindName(span.Kind); kind != "" {
+		out.Tags = append(out.Tags, jaegerTag{Key: "span.kind", Type: "string", Value: strings.ToLower(kind)})
+	}
+	if scope.GetName() != "" {
+		out.Tags = append(out.Tags, jaegerTag{Key: "otel.scope.name", Type: "string", Value: scope.GetName()})
+	}
+	if scope.GetVersion() != "" {
+		out.Tags = append(out.Tags, jaegerTag{Key: "otel.scope.version", Type: "string", Value: scope.GetVersion()})
+	}
+	switch span.Status.GetCode() {
+	case prototrace.Status_STATUS_CODE_ERROR:
+		out.Tags = append(out.Tags,
+			jaegerTag{Key: "error", Type: "bool", Value: true},
+			jaegerTag{Key: "otel.status_code", Type: "string", Value: "ERROR"})
+		if span.Status.Message != "" {
+			out.Tags = append(out.Tags, jaegerTag{Key: "otel.status_description", Type: "string", Value: span.Status.Message})
+		}
+	case prototrace.Status_STATUS_CODE_OK:
+		out.Tags = append(out.Tags, jaegerTag{Key: "otel.status_code", Type: "string", Value: "OK"})
+	}
+
+	for _, event := range span.Events {
+		log := jaegerLog{Timestamp: event.TimeUnixNano / 1000, Fields: []jaegerTag{{Key: "event", Type: "string", Value: event.Name}}}
+		for _, kv := range event.Attributes {
+			log.Fields = append(log.Fields, jaegerTagOf(kv.Key, kv.Value))
+		}
+		out.Logs = append(out.Logs, log)
+	}
+	return out
+}
+
+// jaegerTagOf keeps scalar attributes typed, and renders any other value as a string. JSON has no NaN or infinity, so
+// those doubles are strings too.
+func jaegerTagOf(key string, value *commonpb.AnyValue) jaegerTag {
+	switch v := value.GetValue().(type) {
+	case *commonpb.AnyValue_BoolValue:
+		return jaegerTag{Key: key, Type: "bool", Value: v.BoolValue}
+	case *commonpb.AnyValue_IntValue:
+		return jaegerTag{Key: key, Type: "int64", Value: v.IntValue}
+	case *commonpb.AnyValue_DoubleValue:
+		if math.IsNaN(v.DoubleValue) || math.IsInf(v.DoubleValue, 0) {
+			return jaegerTag{Key: key, Type: "string", Value: valueText(value)}
+		}
+		return jaegerTag{Key: key, Type: "float64", Value: v.DoubleValue}
+	default:
+		return jaegerTag{Key: key, Type: "string", Value: valueText(value)}
+	}
+}
+
+// spanKindName is the span's kind without its prefix, e.g. "SERVER", or "" when unspecified or internal.
+func spanKindName(kind prototrace.Span_SpanKind) string {
+	switch kind {
+	case prototrace.Span_SPAN_KIND_SERVER, prototrace.Span_SPAN_KIND_CLIENT, prototrace.Span_SPAN_KIND_PRODUCER, prototrace.Span_SPAN_KIND_CONSUMER:
+		return strings.TrimPrefix(kind.String(), "SPAN_KIND_")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,62 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestJaegerTagOf(t *testing.T) {
+	double := func(v float64) *commonpb.AnyValue {
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v}}
+	}
+	tests := []struct {
+		name      string
+		value     *commonpb.AnyValue
+		wantType  string
+		wantValue any
+	}{
+		{name: "bool", value: &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: true}}, wantType: "bool", wantValue: true},
+		{name: "int", value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 42}}, wantType: "int64", wantValue: int64(42)},
+		{name: "finite double", value: double(1.5), wantType: "float64", wantValue: 1.5},
+		{name: "NaN", value: double(math.NaN()), wantType: "string", wantValue: "NaN"},
+		{name: "positive infinity", value: double(math.Inf(1)), wantType: "string", wantValue: "+Inf"},
+		{name: "negative infinity", value: double(math.Inf(-1)), wantType: "string", wantValue: "-Inf"},
+		{name: "string", value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "GET"}}, wantType: "string", wantValue: "GET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag := jaegerTagOf("key", tt.value)
+			if tag.Type != tt.wantType || tag.Value != tt.wantValue {
+				t.Errorf("got %s %v, want %s %v", tag.Type, tag.Value, tt.wantType, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestJaegerFlushNonFiniteDouble(t *testing.T) {
+	// a single unencodable attribute must not lose the whole capture
+	span := &prototrace.Span{
+		TraceId:    make([]byte, 16),
+		SpanId:     []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		Name:       "ratio",
+		Attributes: []*commonpb.KeyValue{{Key: "ratio", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: math.NaN()}}}},
+	}
+	jaeger := NewJaegerFormatter()
+	jaeger.FormatTrace(&collectortrace.ExportTraceServiceRequest{ResourceSpans: []*prototrace.ResourceSpans{{
+		ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{span}}},
+	}}})
+
+	var buf bytes.Buffer
+	if err := jaeger.Flush(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !json.Valid(buf.Bytes()) || !bytes.Contains(buf.Bytes(), []byte(`"NaN"`)) {
+		t.Errorf("expected valid JSON with a NaN string tag, got:\n%s", buf.String())
+	}
+}
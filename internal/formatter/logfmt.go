@@ -0,0 +1,71 @@
+package formatter
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+var (
+	_ Formatter = (*LogfmtFormatter)(nil)
+)
+
+// LogfmtFormatter prints one logfmt line per span, log record and data point, with every attribute as its own key.
+// Resource attributes are prefixed with "resource.". See flatColumns for the other keys.
+type LogfmtFormatter struct{}
+
+func NewLogfmtFormatter() *LogfmtFormatter {
+	return &LogfmtFormatter{}
+}
+
+func (l *LogfmtFormatter) FormatTrace(req *collectortrace.ExportTraceServiceRequest) string {
+	return l.render(traceRecords(req))
+}
+
+func (l *LogfmtFormatter) FormatMetric(req *collectormetrics.ExportMetricsServiceRequest) string {
+	return l.render(metricRecords(req))
+}
+
+func (l *LogfmtFormatter) FormatLog(req *collectorlogs.ExportLogsServiceRequest) string {
+	return l.render(logRecords(req))
+}
+
+func (l *LogfmtFormatter) render(records []*Record) string {
+	var buf strings.Builder
+	for _, record := range records {
+		for idx, field := range flattenRecord(record) {
+			if idx > 0 {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString(logfmtKey(field.key))
+			buf.WriteByte('=')
+			buf.WriteString(logfmtValue(field.value))
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// logfmtKey replaces the characters a logfmt key can't contain with underscores.
+func logfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue quotes values that are empty or contain spaces, quotes, equals signs or control characters.
+func logfmtValue(value string) string {
+	if value == "" || strings.ContainsFunc(value, func(r rune) bool {
+		return r == '=' || r == '"' || unicode.IsSpace(r) || unicode.IsControl(r)
+	}) {
+		return strconv.Quote(value)
+	}
+	return value
+}
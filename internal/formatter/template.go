@@ -0,0 +1,431 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+var (
+	_ Formatter = (*TemplateFormatter)(nil)
+)
+
+// Record is the data a TemplateFormatter's template is executed with, once per span, log record or metric data point.
+// Fields that don't apply to the signal are left empty, so one template can render every signal.
+type Record struct {
+	// Signal is "span", "log" or "metric".
+	Signal string
+	// Resource, Attributes and ScopeAttributes nest dotted keys, so "service.name" is available as
+	// .Resource.service.name.
+	Resource        map[string]any
+	Attributes      map[string]any
+	Scope           string
+	ScopeVersion    string
+	ScopeAttributes map[string]any
+
+	// Name is the span name, metric name or log event name.
+	Name string
+	// Time is when the span started, the log record occurred, or the data point was observed.
+	Time      time.Time
+	StartTime time.Time
+	EndTime   time.Time
+	Duration  time.Duration
+
+	TraceID       string
+	SpanID        string
+	ParentSpanID  string
+	Kind          string
+	Status        string
+	StatusMessage string
+	Events        int
+	Links         int
+
+	Severity       string
+	SeverityNumber int32
+	Body           string
+
+	Type        string
+	Description string
+	Unit        string
+	Value       float64
+	Count       uint64
+	Sum         float64
+}
+
+// TemplateFormatter renders each span, log record and data point through a text/template. See Record and TemplateFuncs.
+type TemplateFormatter struct {
+	tmpl *template.Template
+}
+
+func NewTemplateFormatter(text string) (*TemplateFormatter, error) {
+	tmpl, err := template.New("record").Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return &TemplateFormatter{tmpl: tmpl}, nil
+}
+
+// TemplateFuncs are the helpers available to templates, in addition to the text/template builtins:
+//
+//	attr     looks up a dotted key in .Resource or .Attributes: {{attr .Resource "service.name"}}
+//	hex      hex-encodes bytes, e.g. a bytes-valued attribute
+//	short    shortens an ID to its first 8 characters
+//	ms       converts a duration to fractional milliseconds
+//	round    rounds a duration to a unit: {{round .Duration "1ms"}}
+//	trunc    truncates a string to n characters: {{trunc 40 .Body}}
+//	upper    and lower change a string's case
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"attr": attrLookup,
+		"hex": func(b []byte) string {
+			return hex.EncodeToString(b)
+		},
+		"short": func(id string) string {
+			return id[:min(len(id), 8)]
+		},
+		"ms": func(d time.Duration) float64 {
+			return float64(d) / float64(time.Millisecond)
+		},
+		"round": func(d time.Duration, unit string) (time.Duration, error) {
+			m, err := time.ParseDuration(unit)
+			if err != nil {
+				return 0, err
+			}
+			return d.Round(m), nil
+		},
+		"trunc": func(n int, s string) string {
+			return truncate(s, max(n, 1))
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}
+}
+
+func (t *TemplateFormatter) FormatTrace(req *collectortrace.ExportTraceServiceRequest) string {
+	return t.render(traceRecords(req))
+}
+
+func (t *TemplateFormatter) FormatMetric(req *collectormetrics.ExportMetricsServiceRequest) string {
+	return t.render(metricRecords(req))
+}
+
+func (t *TemplateFormatter) FormatLog(req *collectorlogs.ExportLogsServiceRequest) string {
+	return t.render(logRecords(req))
+}
+
+func (t *TemplateFormatter) render(records []*Record) string {
+	var buf bytes.Buffer
+	for _, record := range records {
+		t.execute(&buf, record)
+	}
+	return buf.String()
+}
+
+func (t *TemplateFormatter) execute(buf *bytes.Buffer, record *Record) {
+	start := buf.Len()
+	if err := t.tmpl.Execute(buf, record); err != nil {
+		buf.Truncate(start)
+		log.Printf("Error executing template for %s %q: %v", record.Signal, record.Name, err)
+		return
+	}
+	if buf.Len() > start && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+}
+
+func traceRecords(req *collectortrace.ExportTraceServiceRequest) []*Record {
+	records := make([]*Record, 0)
+	for _, rs := range req.ResourceSpans {
+		resource := nestAttributes(rs.Resource.GetAttributes())
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				record := newRecord("span", resource, ss.Scope)
+				fillSpan(record, span)
+				records = append(records, record)
+			}
+		}
+	}
+	return records
+}
+
+func metricRecords(req *collectormetrics.ExportMetricsServiceRequest) []*Record {
+	records := make([]*Record, 0)
+	for _, rm := range req.ResourceMetrics {
+		resource := nestAttributes(rm.Resource.GetAttributes())
+		for _, sm := range rm.ScopeMetrics {
+			for _, metric := range sm.Metrics {
+				for _, record := range dataPointRecords(metric) {
+					record.Signal = "metric"
+					record.Resource = resource
+					record.Scope, record.ScopeVersion = sm.Scope.GetName(), sm.Scope.GetVersion()
+					record.ScopeAttributes = nestAttributes(sm.Scope.GetAttributes())
+					records = append(records, record)
+				}
+			}
+		}
+	}
+	return records
+}
+
+func logRecords(req *collectorlogs.ExportLogsServiceRequest) []*Record {
+	records := make([]*Record, 0)
+	for _, rl := range req.ResourceLogs {
+		resource := nestAttributes(rl.Resource.GetAttributes())
+		for _, sl := range rl.ScopeLogs {
+			for _, logRecord := range sl.LogRecords {
+				record := newRecord("log", resource, sl.Scope)
+				fillLog(record, logRecord)
+				records = append(records, record)
+			}
+		}
+	}
+	return records
+}
+
+func newRecord(signal string, resource map[string]any, scope *commonpb.InstrumentationScope) *Record {
+	return &Record{
+		Signal:          signal,
+		Resource:        resource,
+		Scope:           scope.GetName(),
+		ScopeVersion:    scope.GetVersion(),
+		ScopeAttributes: nestAttributes(scope.GetAttributes()),
+	}
+}
+
+func fillSpan(record *Record, span *prototrace.Span) {
+	record.Name = span.Name
+	record.Attributes = nestAttributes(span.Attributes)
+	record.Time = unixNano(span.StartTimeUnixNano)
+	record.StartTime = record.Time
+	record.EndTime = unixNano(span.EndTimeUnixNano)
+	// a span missing either timestamp, or ending before it starts, has no meaningful duration
+	if span.StartTimeUnixNano != 0 && span.EndTimeUnixNano >= span.StartTimeUnixNano {
+		record.Duration = record.EndTime.Sub(record.StartTime)
+	}
+	record.TraceID = hex.EncodeToString(span.TraceId)
+	record.SpanID = hex.EncodeToString(span.SpanId)
+	record.ParentSpanID = hex.EncodeToString(span.ParentSpanId)
+	record.Kind = strings.TrimPrefix(span.Kind.String(), "SPAN_KIND_")
+	record.Status = strings.TrimPrefix(span.Status.GetCode().String(), "STATUS_CODE_")
+	record.StatusMessage = span.Status.GetMessage()
+	record.Events = len(span.Events)
+	record.Links = len(span.Links)
+}
+
+func fillLog(record *Record, logRecord *protologs.LogRecord) {
+	record.Name = logRecord.EventName
+	record.Attributes = nestAttributes(logRecord.Attributes)
+	record.Time = unixNano(logRecord.TimeUnixNano)
+	if logRecord.TimeUnixNano == 0 {
+		record.Time = unixNano(logRecord.ObservedTimeUnixNano)
+	}
+	record.TraceID = hex.EncodeToString(logRecord.TraceId)
+	record.SpanID = hex.EncodeToString(logRecord.SpanId)
+	record.Severity = logRecord.SeverityText
+	if record.Severity == "" && logRecord.SeverityNumber != protologs.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED {
+		record.Severity = strings.TrimPrefix(logRecord.SeverityNumber.String(), "SEVERITY_NUMBER_")
+	}
+	record.SeverityNumber = int32(logRecord.SeverityNumber)
+	if logRecord.Body != nil {
+		record.Body = valueText(logRecord.Body)
+	}
+}
+
+// dataPointRecords creates a record per data point, with the metric's definition and the point's value.
+// Value is the point's value for gauges and sums, and its sum for histograms and summaries.
+func dataPointRecords(metric *protometrics.Metric) []*Record {
+	records := make([]*Record, 0)
+	add := func(typ string, attrs []*commonpb.KeyValue, start, ts uint64) *Record {
+		record := &Record{
+			Name:        metric.Name,
+			Description: metric.Description,
+			Unit:        metric.Unit,
+			Type:        typ,
+			Attributes:  nestAttributes(attrs),
+			Time:        unixNano(ts),
+		}
+		if start != 0 {
+			record.StartTime = unixNano(start)
+			record.Duration = record.Time.Sub(record.StartTime)
+		}
+		records = append(records, record)
+		return record
+	}
+
+	switch data := metric.Data.(type) {
+	case *protometrics.Metric_Gauge:
+		for _, dp := range data.Gauge.DataPoints {
+			add("Gauge", dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano).Value = numberDataPointValue(dp)
+		}
+	case *protometrics.Metric_Sum:
+		for _, dp := range data.Sum.DataPoints {
+			add("Sum", dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano).Value = numberDataPointValue(dp)
+		}
+	case *protometrics.Metric_Histogram:
+		for _, dp := range data.Histogram.DataPoints {
+			record := add("Histogram", dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano)
+			record.Count, record.Sum, record.Value = dp.Count, dp.GetSum(), dp.GetSum()
+		}
+	case *protometrics.Metric_ExponentialHistogram:
+		for _, dp := range data.ExponentialHistogram.DataPoints {
+			record := add("ExponentialHistogram", dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano)
+			record.Count, record.Sum, record.Value = dp.Count, dp.GetSum(), dp.GetSum()
+		}
+	case *protometrics.Metric_Summary:
+		for _, dp := range data.Summary.DataPoints {
+			record := add("Summary", dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano)
+			record.Count, record.Sum, record.Value = dp.Count, dp.Sum, dp.Sum
+		}
+	}
+	return records
+}
+
+func numberDataPointValue(dp *protometrics.NumberDataPoint) float64 {
+	switch v := dp.Value.(type) {
+	case *protometrics.NumberDataPoint_AsInt:
+		return float64(v.AsInt)
+	case *protometrics.NumberDataPoint_AsDouble:
+		return v.AsDouble
+	default:
+		return 0
+	}
+}
+
+// nestAttributes converts attributes to nested maps split on dots, so templates can chain field lookups.
+// A key that is both a value and a prefix of other keys, like "http.request" and "http.request.method", keeps its
+// full remaining key at the level where they diverge; attr finds both.
+func nestAttributes(attrs []*commonpb.KeyValue) map[string]any {
+	root := make(map[string]any, len(attrs))
+	for _, kv := range attrs {
+		value := anyValue(kv.Value)
+		m, path := root, kv.Key
+		for {
+			head, rest, nested := strings.Cut(path, ".")
+			if !nested {
+				if _, taken := m[head].(map[string]any); taken {
+					m[path+"."] = value
+				} else {
+					m[head] = value
+				}
+				break
+			}
+			next, exists := m[head]
+			if !exists {
+				next = make(map[string]any)
+				m[head] = next
+			}
+			child, ok := next.(map[string]any)
+			if !ok {
+				m[path] = value
+				break
+			}
+			m, path = child, rest
+		}
+	}
+	return root
+}
+
+// attrLookup finds a dotted key in a map created by nestAttributes, returning "" when it's missing.
+func attrLookup(m map[string]any, key string) any {
+	if value, ok := m[key]; ok {
+		if _, isMap := value.(map[string]any); !isMap {
+			return value
+		}
+		if leaf, ok := m[key+"."]; ok {
+			return leaf
+		}
+		return value
+	}
+	head, rest, nested := strings.Cut(key, ".")
+	if !nested {
+		return ""
+	}
+	child, ok := m[head].(map[string]any)
+	if !ok {
+		return ""
+	}
+	return attrLookup(child, rest)
+}
+
+// anyValue converts an attribute value to its Go equivalent. Key-value lists become maps keyed as-is.
+func anyValue(value *commonpb.AnyValue) any {
+	switch v := value.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return v.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return v.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		return v.DoubleValue
+	case *commonpb.AnyValue_BytesValue:
+		return v.BytesValue
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]any, 0, len(v.ArrayValue.Values))
+		for _, item := range v.ArrayValue.Values {
+			values = append(values, anyValue(item))
+		}
+		return values
+	case *commonpb.AnyValue_KvlistValue:
+		m := make(map[string]any, len(v.KvlistValue.Values))
+		for _, kv := range v.KvlistValue.Values {
+			m[kv.Key] = anyValue(kv.Value)
+		}
+		return m
+	default:
+		return nil
+	}
+}
+
+// valueText renders an attribute value on one line, keeping the order of key-value lists: {msg=failed, rows=0}.
+func valueText(value *commonpb.AnyValue) string {
+	switch v := value.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BytesValue:
+		return "0x" + hex.EncodeToString(v.BytesValue)
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]string, 0, len(v.ArrayValue.Values))
+		for _, item := range v.ArrayValue.Values {
+			values = append(values, valueText(item))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case *commonpb.AnyValue_KvlistValue:
+		values := make([]string, 0, len(v.KvlistValue.Values))
+		for _, kv := range v.KvlistValue.Values {
+			values = append(values, kv.Key+"="+valueText(kv.Value))
+		}
+		return "{" + strings.Join(values, ", ") + "}"
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(anyValue(value))
+	}
+}
+
+func unixNano(nanos uint64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(nanos))
+}
+
+// ServiceName returns the resource's service.name, so templates can use {{.ServiceName}}.
+func (r *Record) ServiceName() string {
+	if s, ok := attrLookup(r.Resource, "service.name").(string); ok {
+		return s
+	}
+	return ""
+}
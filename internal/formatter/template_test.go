@@ -0,0 +1,35 @@
+package formatter
+
+import (
+	"testing"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestTemplateSpanDuration(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end uint64
+		want       string
+	}{
+		{name: "both timestamps", start: 1_000_000, end: 41_000_000, want: "40ms\n"},
+		{name: "missing start", end: 41_000_000, want: "0s\n"},
+		{name: "missing end", start: 1_000_000, want: "0s\n"},
+		{name: "end before start", start: 41_000_000, end: 1_000_000, want: "0s\n"},
+	}
+	f, err := NewTemplateFormatter("{{.Duration}}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := f.FormatTrace(&collectortrace.ExportTraceServiceRequest{ResourceSpans: []*prototrace.ResourceSpans{{
+				ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{{Name: "checkout", StartTimeUnixNano: tt.start, EndTimeUnixNano: tt.end}}}},
+			}}})
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+var (
+	_ Formatter = (*TraceFileFormatter)(nil)
+)
+
+// TraceFileFormatter collects spans and writes them as one JSON document on Flush, in a format trace viewers can open
+// without a backend. Metrics and logs are ignored.
+type TraceFileFormatter struct {
+	mu     sync.Mutex
+	reqs   []*collectortrace.ExportTraceServiceRequest
+	encode func([]*collectortrace.ExportTraceServiceRequest) any
+}
+
+// NewJaegerFormatter writes spans in the JSON format of Jaeger's query API, which the Jaeger UI can load from a file.
+func NewJaegerFormatter() *TraceFileFormatter {
+	return &TraceFileFormatter{encode: func(reqs []*collectortrace.ExportTraceServiceRequest) any {
+		return jaegerDocument(reqs)
+	}}
+}
+
+// NewZipkinFormatter writes spans as a Zipkin v2 JSON array, which the Zipkin UI can load from a file.
+func NewZipkinFormatter() *TraceFileFormatter {
+	return &TraceFileFormatter{encode: func(reqs []*collectortrace.ExportTraceServiceRequest) any {
+		return zipkinSpans(reqs)
+	}}
+}
+
+func (t *TraceFileFormatter) FormatTrace(req *collectortrace.ExportTraceServiceRequest) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reqs = append(t.reqs, req)
+	return ""
+}
+
+func (t *TraceFileFormatter) FormatMetric(*collectormetrics.ExportMetricsServiceRequest) string {
+	return ""
+}
+
+func (t *TraceFileFormatter) FormatLog(*collectorlogs.ExportLogsServiceRequest) string {
+	return ""
+}
+
+// Flush writes the collected spans to w and forgets them.
+func (t *TraceFileFormatter) Flush(w io.Writer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(t.encode(t.reqs)); err != nil {
+		return fmt.Errorf("failed to write trace file: %w", err)
+	}
+	t.reqs = nil
+	return nil
+}
@@ -0,0 +1,203 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jimschubert/otel-relay/internal/analyzer"
+	"github.com/jimschubert/otel-relay/internal/assembler"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+const waterfallWidth = 40
+
+type waterfallRow struct {
+	label string
+	// detail prefixes lines shown under the span, continuing the tree lines around it
+	detail string
+	node   *assembler.Node
+}
+
+// FormatWaterfall renders a reassembled trace as a tree of spans, each with its offset from the start of the trace
+// and a bar showing when it ran.
+func (f *TreeFormatter) FormatWaterfall(trace *assembler.Trace) string {
+	var buf bytes.Buffer
+
+	services := make([]string, 0)
+	trace.Walk(func(node *assembler.Node, _ int) {
+		if service := analyzer.ServiceName(node.Resource); service != "" && !slices.Contains(services, service) {
+			services = append(services, service)
+		}
+	})
+	slices.Sort(services)
+
+	fmt.Fprintf(&buf, "\n%s\n", f.paint(fmt.Sprintf("🌊 TRACE %x", trace.TraceID), ansiBold, ansiCyan))
+	fmt.Fprintf(&buf, "├─ Spans: %d", trace.Spans)
+	if len(services) > 0 {
+		fmt.Fprintf(&buf, " (%s)", strings.Join(services, ", "))
+	}
+	fmt.Fprintf(&buf, "\n")
+	fmt.Fprintf(&buf, "├─ Duration: %v\n", trace.Duration())
+	fmt.Fprintf(&buf, "│\n")
+
+	findings := make(map[string][]analyzer.Finding)
+	for _, a := range f.traces {
+		for _, finding := range a.Trace(trace) {
+			findings[finding.SpanID] = append(findings[finding.SpanID], finding)
+		}
+	}
+
+	rows := waterfallRows(trace.Roots, "", true)
+	labelWidth := 0
+	for _, row := range rows {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(row.label))
+	}
+
+	for _, row := range rows {
+		span := row.node.Span
+		offset := time.Duration(sinceStart(trace, span.StartTimeUnixNano))
+		fmt.Fprintf(&buf, "%s%s  %10s  │%s│ %v",
+			row.label,
+			strings.Repeat(" ", labelWidth-utf8.RuneCountInString(row.label)),
+			"+"+offset.String(),
+			waterfallBar(trace, span),
+			spanDuration(span),
+		)
+		if span.Status.GetCode() == prototrace.Status_STATUS_CODE_ERROR {
+			fmt.Fprintf(&buf, " ❌")
+			if span.Status.Message != "" {
+				fmt.Fprintf(&buf, " %s", f.paint(span.Status.Message, ansiRed))
+			}
+		}
+		if row.node.Orphan {
+			fmt.Fprintf(&buf, " ⚠️  %s", f.paint(fmt.Sprintf("orphan: parent %x not seen", span.ParentSpanId), ansiYellow))
+		}
+		fmt.Fprintf(&buf, "\n")
+
+		if f.verbose {
+			fmt.Fprintf(&buf, "%sSpanID: %x, Kind: %s\n", row.detail, span.SpanId, span.Kind.String())
+		}
+		for _, log := range row.node.Logs {
+			f.buildWaterfallLog(&buf, row.detail, trace, log, row.node)
+		}
+
+		// the orphan marker above already covers the orphan finding
+		spanFindings := slices.DeleteFunc(findings[hex.EncodeToString(span.SpanId)], func(finding analyzer.Finding) bool {
+			return row.node.Orphan && finding.Rule == analyzer.RuleOrphanSpan
+		})
+		f.buildFindings(&buf, row.detail, spanFindings)
+		// a duplicated span ID's findings belong to its first span only
+		delete(findings, hex.EncodeToString(span.SpanId))
+	}
+
+	if len(trace.Logs) > 0 {
+		fmt.Fprintf(&buf, "├─ Logs without a matching span:\n")
+		for _, log := range trace.Logs {
+			f.buildWaterfallLog(&buf, "│  ", trace, log, nil)
+		}
+	}
+
+	fmt.Fprintf(&buf, "└─────────────────────────────────────\n")
+	return buf.String()
+}
+
+// buildWaterfallLog renders a correlated log record on one line, with its offset from the start of the trace. The
+// log's service is shown when it differs from its span's, and its span ID when it has no span in the trace.
+func (f *TreeFormatter) buildWaterfallLog(buf *bytes.Buffer, prefix string, trace *assembler.Trace, log *assembler.Log, node *assembler.Node) {
+	record := log.Record
+	offset := time.Duration(int64(log.Time()) - int64(trace.Start))
+
+	fmt.Fprintf(buf, "%s📝 %s", prefix, formatOffset(offset))
+	width := utf8.RuneCountInString(prefix) + 3 + len(formatOffset(offset))
+	if record.SeverityText != "" {
+		fmt.Fprintf(buf, " %s", f.paint(record.SeverityText, severityColor(record.SeverityNumber)...))
+		width += 1 + utf8.RuneCountInString(record.SeverityText)
+	}
+
+	var extra string
+	if service := analyzer.ServiceName(log.Resource); service != "" && (node == nil || service != analyzer.ServiceName(node.Resource)) {
+		extra += " [" + service + "]"
+	}
+	if node == nil && len(record.SpanId) > 0 {
+		extra += fmt.Sprintf(" (span %x)", record.SpanId)
+	}
+	buf.WriteString(extra)
+	width += utf8.RuneCountInString(extra)
+
+	if record.Body != nil {
+		body := f.attributeValueToString(record.Body)
+		if f.width > 0 {
+			body = f.fit(strings.Repeat(" ", width+1), body)
+		} else if !f.verbose {
+			body = truncate(body, 100)
+		}
+		fmt.Fprintf(buf, " %s", body)
+	}
+	fmt.Fprintf(buf, "\n")
+}
+
+// formatOffset formats d with an explicit sign, e.g. "+41ms" or "-2ms".
+func formatOffset(d time.Duration) string {
+	if d < 0 {
+		return d.String()
+	}
+	return "+" + d.String()
+}
+
+// waterfallRows flattens the tree depth first, labelling each span with its tree branch, name and service.
+// Top level spans never close their branch, since the trace's footer does.
+func waterfallRows(nodes []*assembler.Node, prefix string, top bool) []waterfallRow {
+	rows := make([]waterfallRow, 0, len(nodes))
+	for idx, node := range nodes {
+		last := !top && idx == len(nodes)-1
+
+		branch, childPrefix := "├─ ", prefix+"│  "
+		if last {
+			branch, childPrefix = "└─ ", prefix+"   "
+		}
+
+		label := prefix + branch + node.Span.Name
+		if service := analyzer.ServiceName(node.Resource); service != "" {
+			label += " [" + service + "]"
+		}
+
+		detail := childPrefix + "   "
+		if len(node.Children) > 0 {
+			detail = childPrefix + "│  "
+		}
+
+		rows = append(rows, waterfallRow{label: label, detail: detail, node: node})
+		rows = append(rows, waterfallRows(node.Children, childPrefix, false)...)
+	}
+	return rows
+}
+
+func waterfallBar(trace *assembler.Trace, span *prototrace.Span) string {
+	from, to := 0, waterfallWidth
+	if total := float64(trace.End - trace.Start); total > 0 {
+		end := max(span.EndTimeUnixNano, span.StartTimeUnixNano)
+		from = int(float64(sinceStart(trace, span.StartTimeUnixNano)) / total * waterfallWidth)
+		to = int(math.Ceil(float64(sinceStart(trace, end)) / total * waterfallWidth))
+		from = min(from, waterfallWidth-1)
+		to = min(max(to, from+1), waterfallWidth)
+	}
+	return strings.Repeat(" ", from) + strings.Repeat("█", to-from) + strings.Repeat(" ", waterfallWidth-to)
+}
+
+// sinceStart is the time from the trace's start to nanos, or 0 for a time before it, such as an unset start time.
+func sinceStart(trace *assembler.Trace, nanos uint64) uint64 {
+	if nanos < trace.Start {
+		return 0
+	}
+	return nanos - trace.Start
+}
+
+func spanDuration(span *prototrace.Span) time.Duration {
+	return time.Unix(0, int64(span.EndTimeUnixNano)).Sub(time.Unix(0, int64(span.StartTimeUnixNano)))
+}
@@ -0,0 +1,26 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jimschubert/otel-relay/internal/assembler"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestFormatWaterfallSpanBeforeTraceStart(t *testing.T) {
+	// a span without a start time sorts before the trace's start, which must not underflow its offset or bar
+	span := &prototrace.Span{TraceId: make([]byte, 16), SpanId: []byte{1, 2, 3, 4, 5, 6, 7, 8}, Name: "unset", EndTimeUnixNano: 1001}
+	trace := &assembler.Trace{
+		TraceID: span.TraceId,
+		Roots:   []*assembler.Node{{Span: span}},
+		Spans:   1,
+		Start:   1000,
+		End:     1001,
+	}
+
+	out := NewTreeFormatter(false).FormatWaterfall(trace)
+	if !strings.Contains(out, "unset") || !strings.Contains(out, "+0s") {
+		t.Errorf("expected the span at offset +0s, got:\n%s", out)
+	}
+}
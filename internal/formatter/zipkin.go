@@ -0,0 +1,119 @@
+package formatter
+
+import (
+	"cmp"
+	"encoding/hex"
+	"strings"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+type zipkinSpan struct {
+	TraceID        string             `json:"traceId"`
+	ID             string             `json:"id"`
+	ParentID       string             `json:"parentId,omitempty"`
+	Name           string             `json:"name,omitempty"`
+	Kind           string             `json:"kind,omitempty"`
+	Timestamp      uint64             `json:"timestamp,omitempty"`
+	Duration       uint64             `json:"duration,omitempty"`
+	LocalEndpoint  *zipkinEndpoint    `json:"localEndpoint,omitempty"`
+	RemoteEndpoint *zipkinEndpoint    `json:"remoteEndpoint,omitempty"`
+	Annotations    []zipkinAnnotation `json:"annotations,omitempty"`
+	Tags           map[string]string  `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp uint64 `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+// zipkinSpans converts spans the way the OpenTelemetry Zipkin exporter does: times in microseconds, attributes,
+// resource attributes, scope and status as string tags, and events as annotations. Zipkin has no links, so they're
+// dropped.
+func zipkinSpans(reqs []*collectortrace.ExportTraceServiceRequest) []zipkinSpan {
+	spans := make([]zipkinSpan, 0)
+	for _, req := range reqs {
+		for _, rs := range req.ResourceSpans {
+			service := "unknown_service"
+			resourceTags := make(map[string]string)
+			for _, kv := range rs.Resource.GetAttributes() {
+				if kv.Key == "service.name" {
+					service = kv.Value.GetStringValue()
+				} else {
+					resourceTags[kv.Key] = valueText(kv.Value)
+				}
+			}
+
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					out := zipkinSpanOf(span, ss.Scope)
+					out.LocalEndpoint = &zipkinEndpoint{ServiceName: service}
+					for key, value := range resourceTags {
+						if _, ok := out.Tags[key]; !ok {
+							out.Tags[key] = value
+						}
+					}
+					if len(out.Tags) == 0 {
+						out.Tags = nil
+					}
+					spans = append(spans, out)
+				}
+			}
+		}
+	}
+	return spans
+}
+
+func zipkinSpanOf(span *prototrace.Span, scope *commonpb.InstrumentationScope) zipkinSpan {
+	out := zipkinSpan{
+		TraceID:   hex.EncodeToString(span.TraceId),
+		ID:        hex.EncodeToString(span.SpanId),
+		ParentID:  hex.EncodeToString(span.ParentSpanId),
+		Name:      span.Name,
+		Kind:      spanKindName(span.Kind),
+		Timestamp: span.StartTimeUnixNano / 1000,
+		Tags:      make(map[string]string, len(span.Attributes)),
+	}
+	if span.EndTimeUnixNano > span.StartTimeUnixNano {
+		out.Duration = (span.EndTimeUnixNano - span.StartTimeUnixNano) / 1000
+	}
+
+	for _, kv := range span.Attributes {
+		out.Tags[kv.Key] = valueText(kv.Value)
+	}
+	if peer := out.Tags["peer.service"]; peer != "" && (out.Kind == "CLIENT" || out.Kind == "PRODUCER") {
+		out.RemoteEndpoint = &zipkinEndpoint{ServiceName: peer}
+	}
+	if scope.GetName() != "" {
+		out.Tags["otel.scope.name"] = scope.GetName()
+	}
+	if scope.GetVersion() != "" {
+		out.Tags["otel.scope.version"] = scope.GetVersion()
+	}
+	switch span.Status.GetCode() {
+	case prototrace.Status_STATUS_CODE_ERROR:
+		out.Tags["otel.status_code"] = "ERROR"
+		out.Tags["error"] = cmp.Or(span.Status.Message, "true")
+	case prototrace.Status_STATUS_CODE_OK:
+		out.Tags["otel.status_code"] = "OK"
+	}
+
+	for _, event := range span.Events {
+		value := event.Name
+		if len(event.Attributes) > 0 {
+			attrs := make([]string, 0, len(event.Attributes))
+			for _, kv := range event.Attributes {
+				attrs = append(attrs, kv.Key+"="+valueText(kv.Value))
+			}
+			value += " {" + strings.Join(attrs, ", ") + "}"
+		}
+		out.Annotations = append(out.Annotations, zipkinAnnotation{Timestamp: event.TimeUnixNano / 1000, Value: value})
+	}
+	return out
+}
@@ -12,12 +12,14 @@ import (
 	"time"
 )
 
-func EnsureServerRunning(path string) error {
+// EnsureServerRunning starts the daemon for path in a new process, passing args on to it, unless one is already running.
+// It reports whether it started the daemon, since an existing daemon keeps the args it was started with.
+func EnsureServerRunning(path string, args ...string) (bool, error) {
 	conn, err := net.DialTimeout("unix", path, 100*time.Millisecond)
 	if err == nil {
 		conn.Close()
 		log.Println("daemon gRPC server already running")
-		return nil
+		return false, nil
 	}
 
 	if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, syscall.ENOENT) && !errors.Is(err, syscall.ECONNREFUSED) {
@@ -26,11 +28,11 @@ func EnsureServerRunning(path string) error {
 		log.Printf("daemon gRPC server not found at %s, starting daemon...", path)
 	}
 
-	cmd := exec.Command(os.Args[0], "--daemon", path)
+	cmd := exec.Command(os.Args[0], append([]string{"--daemon", path}, args...)...)
 	cmd.Stdout = nil
 	cmd.Stderr = os.Stderr
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start gRPC server daemon: %w", err)
+		return false, fmt.Errorf("failed to start gRPC server daemon: %w", err)
 	}
 
 	go cmd.Wait()
@@ -41,21 +43,21 @@ func EnsureServerRunning(path string) error {
 		if err == nil {
 			conn.Close()
 			log.Println("gRPC daemon started successfully")
-			return nil
+			return true, nil
 		}
 		if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, syscall.ENOENT) && !errors.Is(err, syscall.ECONNREFUSED) {
 			log.Printf("Error checking gRPC server: %v", err)
 		}
 		if cmd.Err != nil {
-			return fmt.Errorf("gRPC server process exited with error: %w", cmd.Err)
+			return false, fmt.Errorf("gRPC server process exited with error: %w", cmd.Err)
 		}
 	}
 
-	return fmt.Errorf("gRPC server did not start in time")
+	return false, fmt.Errorf("gRPC server did not start in time")
 }
 
-func RunDaemon(path string) {
-	server := NewServer(path)
+func RunDaemon(path string, opts ...Option) {
+	server := NewServer(path, opts...)
 	if err := server.Start(); err != nil {
 		_ = server.Close()
 		log.Fatalf("Failed to start gRPC daemon: %v", err)
@@ -0,0 +1,142 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/jimschubert/otel-relay/proto/inspector"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultHistory is the number of trace and log exports the daemon retains for GetTrace.
+const DefaultHistory = 10000
+
+// maxHistoryBytes bounds the size of the retained exports, evicting the oldest first, so a history of large exports
+// can't grow the daemon without limit.
+const maxHistoryBytes = 64 << 20
+
+// WithHistory retains the last size trace and log exports, up to 64 MiB in total, so GetTrace can find a trace after
+// it has been streamed. A size of 0 disables the history.
+func WithHistory(size int) Option {
+	return func(s *Server) {
+		s.history = newHistory(size, maxHistoryBytes)
+	}
+}
+
+// history is a ring buffer of the most recent trace and log events, bounded by count and by total payload size.
+type history struct {
+	mu       sync.Mutex
+	events   []*inspector.TelemetryEvent
+	start    int
+	count    int
+	size     int
+	maxBytes int
+}
+
+func newHistory(size, maxBytes int) *history {
+	return &history{events: make([]*inspector.TelemetryEvent, max(size, 0)), maxBytes: maxBytes}
+}
+
+func (h *history) add(event *inspector.TelemetryEvent) {
+	if event.Type != inspector.TelemetryType_TELEMETRY_TYPE_TRACE && event.Type != inspector.TelemetryType_TELEMETRY_TYPE_LOG {
+		return
+	}
+	// an export larger than the whole history would evict everything and still not fit
+	if len(h.events) == 0 || len(event.Data) > h.maxBytes {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for h.count == len(h.events) || (h.count > 0 && h.size+len(event.Data) > h.maxBytes) {
+		h.size -= len(h.events[h.start].Data)
+		h.events[h.start] = nil
+		h.start = (h.start + 1) % len(h.events)
+		h.count--
+	}
+	h.events[(h.start+h.count)%len(h.events)] = event
+	h.count++
+	h.size += len(event.Data)
+}
+
+// snapshot returns the retained events, oldest first.
+func (h *history) snapshot() []*inspector.TelemetryEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := make([]*inspector.TelemetryEvent, 0, h.count)
+	for i := range h.count {
+		events = append(events, h.events[(h.start+i)%len(h.events)])
+	}
+	return events
+}
+
+func (s *Server) GetTrace(_ context.Context, req *inspector.TraceRequest) (*inspector.TraceResponse, error) {
+	if len(req.TraceId) != 16 {
+		return nil, status.Errorf(codes.InvalidArgument, "trace ID must be 16 bytes, got %d", len(req.TraceId))
+	}
+
+	events := s.history.snapshot()
+	resp := &inspector.TraceResponse{History: int32(len(events))}
+	for _, event := range events {
+		data, err := pruneToTrace(event, req.TraceId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to search history: %v", err)
+		}
+		if data != nil {
+			resp.Events = append(resp.Events, &inspector.TelemetryEvent{Type: event.Type, Data: data})
+		}
+	}
+	return resp, nil
+}
+
+// pruneToTrace returns the event's request with only the spans or log records of the trace, or nil when it has none.
+func pruneToTrace(event *inspector.TelemetryEvent, traceID []byte) ([]byte, error) {
+	var req proto.Message
+	found := false
+	switch event.Type {
+	case inspector.TelemetryType_TELEMETRY_TYPE_TRACE:
+		var traces collectortrace.ExportTraceServiceRequest
+		if err := proto.Unmarshal(event.Data, &traces); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal trace: %w", err)
+		}
+		for _, rs := range traces.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				ss.Spans = slices.DeleteFunc(ss.Spans, func(span *prototrace.Span) bool { return !bytes.Equal(span.TraceId, traceID) })
+				found = found || len(ss.Spans) > 0
+			}
+			rs.ScopeSpans = slices.DeleteFunc(rs.ScopeSpans, func(ss *prototrace.ScopeSpans) bool { return len(ss.Spans) == 0 })
+		}
+		traces.ResourceSpans = slices.DeleteFunc(traces.ResourceSpans, func(rs *prototrace.ResourceSpans) bool { return len(rs.ScopeSpans) == 0 })
+		req = &traces
+	case inspector.TelemetryType_TELEMETRY_TYPE_LOG:
+		var logs collectorlogs.ExportLogsServiceRequest
+		if err := proto.Unmarshal(event.Data, &logs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal log: %w", err)
+		}
+		for _, rl := range logs.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				sl.LogRecords = slices.DeleteFunc(sl.LogRecords, func(record *protologs.LogRecord) bool { return !bytes.Equal(record.TraceId, traceID) })
+				found = found || len(sl.LogRecords) > 0
+			}
+			rl.ScopeLogs = slices.DeleteFunc(rl.ScopeLogs, func(sl *protologs.ScopeLogs) bool { return len(sl.LogRecords) == 0 })
+		}
+		logs.ResourceLogs = slices.DeleteFunc(logs.ResourceLogs, func(rl *protologs.ResourceLogs) bool { return len(rl.ScopeLogs) == 0 })
+		req = &logs
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return proto.Marshal(req)
+}
@@ -0,0 +1,49 @@
+package grpcserver
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jimschubert/otel-relay/proto/inspector"
+)
+
+func TestHistory(t *testing.T) {
+	trace := func(size int) *inspector.TelemetryEvent {
+		return &inspector.TelemetryEvent{Type: inspector.TelemetryType_TELEMETRY_TYPE_TRACE, Data: make([]byte, size)}
+	}
+	tests := []struct {
+		name     string
+		size     int
+		maxBytes int
+		add      []*inspector.TelemetryEvent
+		want     []int // sizes of the retained events, oldest first
+	}{
+		{name: "disabled", size: 0, maxBytes: 100, add: []*inspector.TelemetryEvent{trace(1)}, want: []int{}},
+		{name: "under both limits", size: 3, maxBytes: 100, add: []*inspector.TelemetryEvent{trace(1), trace(2)}, want: []int{1, 2}},
+		{name: "evicts the oldest by count", size: 2, maxBytes: 100, add: []*inspector.TelemetryEvent{trace(1), trace(2), trace(3)}, want: []int{2, 3}},
+		{name: "evicts the oldest by bytes", size: 10, maxBytes: 10, add: []*inspector.TelemetryEvent{trace(4), trace(4), trace(5)}, want: []int{4, 5}},
+		{name: "evicts several for a large export", size: 10, maxBytes: 10, add: []*inspector.TelemetryEvent{trace(3), trace(3), trace(3), trace(9)}, want: []int{9}},
+		{name: "skips an export over the byte limit", size: 10, maxBytes: 10, add: []*inspector.TelemetryEvent{trace(4), trace(11)}, want: []int{4}},
+		{name: "skips metrics", size: 10, maxBytes: 10, add: []*inspector.TelemetryEvent{{Type: inspector.TelemetryType_TELEMETRY_TYPE_METRIC, Data: make([]byte, 1)}}, want: []int{}},
+		{
+			name: "wraps around", size: 3, maxBytes: 100,
+			add:  []*inspector.TelemetryEvent{trace(1), trace(2), trace(3), trace(4), trace(5), trace(6), trace(7)},
+			want: []int{5, 6, 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHistory(tt.size, tt.maxBytes)
+			for _, event := range tt.add {
+				h.add(event)
+			}
+			got := make([]int, 0)
+			for _, event := range h.snapshot() {
+				got = append(got, len(event.Data))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got sizes %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
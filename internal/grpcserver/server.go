@@ -5,6 +5,7 @@ import (
 	"io"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"sync"
 	"time"
@@ -22,16 +23,31 @@ type Server struct {
 	path      string
 	listener  net.Listener
 	grpc      *grpc.Server
-	streams   map[inspector.InspectorService_StreamServer]chan *inspector.TelemetryEvent
+	streams   map[chan *inspector.TelemetryEvent]struct{}
 	mu        sync.RWMutex
 	broadcast chan *inspector.TelemetryEvent
+	sizing    chan *inspector.TelemetryEvent
 	closeOnce sync.Once
 
+	webAddr string
+	web     *http.Server
+
+	history *history
+
 	stats *DaemonStats
 }
 
-func (s *Server) GetStats(_ context.Context, _ *inspector.StatsRequest) (*inspector.StatsResponse, error) {
-	return &inspector.StatsResponse{
+type Option func(*Server)
+
+// WithWeb serves the web UI and its event stream over HTTP on addr, e.g. ":8080". An empty address disables it.
+func WithWeb(addr string) Option {
+	return func(s *Server) {
+		s.webAddr = addr
+	}
+}
+
+func (s *Server) GetStats(_ context.Context, req *inspector.StatsRequest) (*inspector.StatsResponse, error) {
+	resp := &inspector.StatsResponse{
 		TracesObserved:  s.stats.tracesObserved.Load(),
 		MetricsObserved: s.stats.metricsObserved.Load(),
 		LogsObserved:    s.stats.logsObserved.Load(),
@@ -40,16 +56,26 @@ func (s *Server) GetStats(_ context.Context, _ *inspector.StatsRequest) (*inspec
 		ActiveReaders: s.stats.activeReaders.Load(),
 		ActiveWriters: s.stats.activeWriters.Load(),
 		UptimeSeconds: int64(time.Since(s.stats.startTime).Seconds()),
-	}, nil
+	}
+	if req.GetBreakdown() {
+		s.stats.sizes.fill(resp)
+	}
+	return resp, nil
 }
 
-func NewServer(path string) *Server {
-	return &Server{
+func NewServer(path string, opts ...Option) *Server {
+	s := &Server{
 		path:      path,
-		streams:   make(map[inspector.InspectorService_StreamServer]chan *inspector.TelemetryEvent),
+		streams:   make(map[chan *inspector.TelemetryEvent]struct{}),
 		broadcast: make(chan *inspector.TelemetryEvent, 1000),
-		stats:     &DaemonStats{},
+		sizing:    make(chan *inspector.TelemetryEvent, 1000),
+		stats:     &DaemonStats{sizes: newSizeStats()},
+		history:   newHistory(DefaultHistory, maxHistoryBytes),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Server) Start() error {
@@ -65,7 +91,14 @@ func (s *Server) Start() error {
 	s.grpc = grpc.NewServer()
 	inspector.RegisterInspectorServiceServer(s.grpc, s)
 
+	if s.webAddr != "" {
+		if err := s.startWeb(); err != nil {
+			return err
+		}
+	}
+
 	go s.broadcastLoop()
+	go s.sizeLoop()
 	go func() {
 		s.stats.StartTime(time.Now())
 		if err := s.grpc.Serve(ln); err != nil {
@@ -79,12 +112,15 @@ func (s *Server) Start() error {
 func (s *Server) Close() error {
 	var err error
 	s.closeOnce.Do(func() {
+		if s.web != nil {
+			err = s.web.Close()
+		}
 		if s.grpc != nil {
 			s.grpc.GracefulStop()
 		}
 		close(s.broadcast)
 		s.mu.Lock()
-		for _, ch := range s.streams {
+		for ch := range s.streams {
 			close(ch)
 		}
 		s.streams = nil
@@ -95,19 +131,8 @@ func (s *Server) Close() error {
 }
 
 func (s *Server) Stream(stream inspector.InspectorService_StreamServer) error {
-	streamCh := make(chan *inspector.TelemetryEvent, 100)
-	s.mu.Lock()
-	s.streams[stream] = streamCh
-	s.stats.activeReaders.Store(int32(len(s.streams)))
-	s.mu.Unlock()
-
-	defer func() {
-		s.mu.Lock()
-		delete(s.streams, stream)
-		s.stats.activeReaders.Store(int32(len(s.streams)))
-		s.mu.Unlock()
-		close(streamCh)
-	}()
+	streamCh := s.subscribe()
+	defer s.unsubscribe(streamCh)
 
 	errCh := make(chan error, 1)
 	go func() {
@@ -139,6 +164,30 @@ func (s *Server) Stream(stream inspector.InspectorService_StreamServer) error {
 	}
 }
 
+// subscribe registers a reader for broadcast events. The channel is closed by unsubscribe, or when the server closes.
+func (s *Server) subscribe() chan *inspector.TelemetryEvent {
+	ch := make(chan *inspector.TelemetryEvent, 100)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.streams == nil {
+		close(ch)
+		return ch
+	}
+	s.streams[ch] = struct{}{}
+	s.stats.activeReaders.Store(int32(len(s.streams)))
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan *inspector.TelemetryEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.streams[ch]; ok {
+		delete(s.streams, ch)
+		close(ch)
+	}
+	s.stats.activeReaders.Store(int32(len(s.streams)))
+}
+
 func (s *Server) Emit(ctx context.Context, event *inspector.TelemetryEvent) (*inspector.EmitResponse, error) {
 	// fyi: active writers here are in-process writes, not "long-lived clients"
 	// this differs from active readers, which are long-lived streaming clients.
@@ -168,9 +217,18 @@ func (s *Server) Emit(ctx context.Context, event *inspector.TelemetryEvent) (*in
 }
 
 func (s *Server) broadcastLoop() {
+	defer close(s.sizing)
 	for event := range s.broadcast {
+		// Size accounting decodes every event, so it runs on its own worker and drops samples rather than
+		// holding up readers.
+		select {
+		case s.sizing <- event:
+		default:
+		}
+		s.history.add(event)
+
 		s.mu.RLock()
-		for _, ch := range s.streams {
+		for ch := range s.streams {
 			select {
 			case ch <- event:
 			default:
@@ -179,3 +237,11 @@ func (s *Server) broadcastLoop() {
 		s.mu.RUnlock()
 	}
 }
+
+func (s *Server) sizeLoop() {
+	for event := range s.sizing {
+		if err := s.stats.sizes.record(event); err != nil {
+			log.Printf("Error accounting event size: %v", err)
+		}
+	}
+}
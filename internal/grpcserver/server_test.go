@@ -0,0 +1,63 @@
+package grpcserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jimschubert/otel-relay/proto/inspector"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBroadcastDoesNotWaitForSizeAccounting(t *testing.T) {
+	s := NewServer(t.TempDir() + "/relay.sock")
+	stream := s.subscribe()
+
+	// No size worker is running, so the sizing queue fills up after cap(s.sizing) events; every event after that
+	// must still reach the reader.
+	done := make(chan struct{})
+	go func() {
+		s.broadcastLoop()
+		close(done)
+	}()
+
+	for idx := range cap(s.sizing) * 3 {
+		s.broadcast <- &inspector.TelemetryEvent{Type: inspector.TelemetryType_TELEMETRY_TYPE_TRACE, Data: []byte{}}
+		select {
+		case <-stream:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %d was not broadcast while the size worker was stalled", idx)
+		}
+	}
+	close(s.broadcast)
+	<-done
+
+	if got := len(s.sizing); got != cap(s.sizing) {
+		t.Errorf("got %d queued for sizing, want the queue full at %d", got, cap(s.sizing))
+	}
+}
+
+func TestSizeLoopRecordsEvents(t *testing.T) {
+	data, err := proto.Marshal(&collectortrace.ExportTraceServiceRequest{ResourceSpans: []*prototrace.ResourceSpans{{
+		Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+			{Key: "service.name", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "checkout"}}},
+		}},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer(t.TempDir() + "/relay.sock")
+	s.sizing <- &inspector.TelemetryEvent{Type: inspector.TelemetryType_TELEMETRY_TYPE_TRACE, Data: data}
+	close(s.sizing)
+	s.sizeLoop()
+
+	resp := &inspector.StatsResponse{}
+	s.stats.sizes.fill(resp)
+	if len(resp.Services) != 1 || resp.Services[0].Service != "checkout" || resp.Services[0].Count != 1 {
+		t.Errorf("got services %v, want one checkout export", resp.Services)
+	}
+}
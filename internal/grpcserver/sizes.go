@@ -0,0 +1,235 @@
+package grpcserver
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/jimschubert/otel-relay/internal/analyzer"
+	"github.com/jimschubert/otel-relay/proto/inspector"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// OversizedBytes is the encoded size above which an attribute value or span event is reported as oversized.
+	OversizedBytes = 4096
+
+	// maxTrackedAttributes bounds the attribute keys tracked across all services; keys seen after that are counted
+	// under otherAttributes.
+	maxTrackedAttributes = 10000
+	maxTrackedOversized  = 1000
+	otherAttributes      = "<other>"
+
+	// maxReported bounds the attributes and oversized items returned by GetStats.
+	maxReported = 100
+)
+
+type serviceSignal struct {
+	service string
+	signal  inspector.TelemetryType
+}
+
+type serviceAttribute struct {
+	service string
+	key     string
+}
+
+type oversizedTarget struct {
+	service string
+	signal  inspector.TelemetryType
+	target  string
+}
+
+// sizeStats accounts for the encoded size of every event by service, signal and attribute key, and records
+// oversized attribute values and span events.
+type sizeStats struct {
+	mu         sync.Mutex
+	services   map[serviceSignal]*inspector.ServiceBytes
+	attributes map[serviceAttribute]*inspector.AttributeBytes
+	oversized  map[oversizedTarget]*inspector.OversizedItem
+}
+
+func newSizeStats() *sizeStats {
+	return &sizeStats{
+		services:   make(map[serviceSignal]*inspector.ServiceBytes),
+		attributes: make(map[serviceAttribute]*inspector.AttributeBytes),
+		oversized:  make(map[oversizedTarget]*inspector.OversizedItem),
+	}
+}
+
+func (s *sizeStats) record(event *inspector.TelemetryEvent) error {
+	switch event.Type {
+	case inspector.TelemetryType_TELEMETRY_TYPE_TRACE:
+		var req collectortrace.ExportTraceServiceRequest
+		if err := proto.Unmarshal(event.Data, &req); err != nil {
+			return fmt.Errorf("failed to unmarshal trace: %w", err)
+		}
+		s.recordTraces(&req)
+	case inspector.TelemetryType_TELEMETRY_TYPE_METRIC:
+		var req collectormetrics.ExportMetricsServiceRequest
+		if err := proto.Unmarshal(event.Data, &req); err != nil {
+			return fmt.Errorf("failed to unmarshal metric: %w", err)
+		}
+		s.recordMetrics(&req)
+	case inspector.TelemetryType_TELEMETRY_TYPE_LOG:
+		var req collectorlogs.ExportLogsServiceRequest
+		if err := proto.Unmarshal(event.Data, &req); err != nil {
+			return fmt.Errorf("failed to unmarshal log: %w", err)
+		}
+		s.recordLogs(&req)
+	}
+	return nil
+}
+
+func (s *sizeStats) recordTraces(req *collectortrace.ExportTraceServiceRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	signal := inspector.TelemetryType_TELEMETRY_TYPE_TRACE
+	for _, rs := range req.ResourceSpans {
+		service := analyzer.ServiceName(rs.Resource)
+		s.addService(service, signal, proto.Size(rs))
+		s.addAttributes(service, signal, "resource", rs.Resource.GetAttributes())
+
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				on := fmt.Sprintf("span %q", span.Name)
+				s.addAttributes(service, signal, on, span.Attributes)
+				for _, event := range span.Events {
+					eventOn := fmt.Sprintf("event %q on %s", event.Name, on)
+					if size := proto.Size(event); size > OversizedBytes {
+						s.addOversized(service, signal, eventOn, size)
+					}
+					s.addAttributes(service, signal, eventOn, event.Attributes)
+				}
+				for _, link := range span.Links {
+					s.addAttributes(service, signal, "link on "+on, link.Attributes)
+				}
+			}
+		}
+	}
+}
+
+func (s *sizeStats) recordMetrics(req *collectormetrics.ExportMetricsServiceRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	signal := inspector.TelemetryType_TELEMETRY_TYPE_METRIC
+	for _, rm := range req.ResourceMetrics {
+		service := analyzer.ServiceName(rm.Resource)
+		s.addService(service, signal, proto.Size(rm))
+		s.addAttributes(service, signal, "resource", rm.Resource.GetAttributes())
+
+		for _, sm := range rm.ScopeMetrics {
+			for _, metric := range sm.Metrics {
+				on := fmt.Sprintf("metric %q", metric.Name)
+				for _, attrs := range analyzer.DataPointAttributes(metric) {
+					s.addAttributes(service, signal, on, attrs)
+				}
+			}
+		}
+	}
+}
+
+func (s *sizeStats) recordLogs(req *collectorlogs.ExportLogsServiceRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	signal := inspector.TelemetryType_TELEMETRY_TYPE_LOG
+	for _, rl := range req.ResourceLogs {
+		service := analyzer.ServiceName(rl.Resource)
+		s.addService(service, signal, proto.Size(rl))
+		s.addAttributes(service, signal, "resource", rl.Resource.GetAttributes())
+
+		for _, sl := range rl.ScopeLogs {
+			for _, record := range sl.LogRecords {
+				s.addAttributes(service, signal, "log record", record.Attributes)
+			}
+		}
+	}
+}
+
+func (s *sizeStats) addService(service string, signal inspector.TelemetryType, size int) {
+	key := serviceSignal{service: service, signal: signal}
+	entry := s.services[key]
+	if entry == nil {
+		entry = &inspector.ServiceBytes{Service: service, Type: signal}
+		s.services[key] = entry
+	}
+	entry.Bytes += uint64(size)
+	entry.Count++
+}
+
+func (s *sizeStats) addAttributes(service string, signal inspector.TelemetryType, on string, attrs []*commonpb.KeyValue) {
+	for _, kv := range attrs {
+		key := serviceAttribute{service: service, key: kv.Key}
+		entry := s.attributes[key]
+		if entry == nil {
+			if len(s.attributes) >= maxTrackedAttributes {
+				key.key = otherAttributes
+				entry = s.attributes[key]
+			}
+			if entry == nil {
+				entry = &inspector.AttributeBytes{Service: service, Key: key.key}
+				s.attributes[key] = entry
+			}
+		}
+
+		size := uint64(proto.Size(kv))
+		entry.Bytes += size
+		entry.Count++
+		entry.MaxBytes = max(entry.MaxBytes, size)
+
+		if valueSize := proto.Size(kv.Value); valueSize > OversizedBytes {
+			s.addOversized(service, signal, fmt.Sprintf("attribute %q on %s", kv.Key, on), valueSize)
+		}
+	}
+}
+
+func (s *sizeStats) addOversized(service string, signal inspector.TelemetryType, target string, size int) {
+	key := oversizedTarget{service: service, signal: signal, target: target}
+	entry := s.oversized[key]
+	if entry == nil {
+		if len(s.oversized) >= maxTrackedOversized {
+			return
+		}
+		entry = &inspector.OversizedItem{Service: service, Type: signal, Target: target}
+		s.oversized[key] = entry
+	}
+	entry.MaxBytes = max(entry.MaxBytes, uint64(size))
+	entry.Count++
+}
+
+// fill adds the breakdown to resp: every service and signal, and the largest attributes and oversized items.
+func (s *sizeStats) fill(resp *inspector.StatsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.services {
+		resp.Services = append(resp.Services, proto.CloneOf(entry))
+	}
+	slices.SortFunc(resp.Services, func(a, b *inspector.ServiceBytes) int {
+		return cmp.Or(cmp.Compare(b.Bytes, a.Bytes), cmp.Compare(a.Service, b.Service), cmp.Compare(a.Type, b.Type))
+	})
+
+	for _, entry := range s.attributes {
+		resp.Attributes = append(resp.Attributes, proto.CloneOf(entry))
+	}
+	slices.SortFunc(resp.Attributes, func(a, b *inspector.AttributeBytes) int {
+		return cmp.Or(cmp.Compare(b.Bytes, a.Bytes), cmp.Compare(a.Service, b.Service), cmp.Compare(a.Key, b.Key))
+	})
+	resp.Attributes = resp.Attributes[:min(len(resp.Attributes), maxReported)]
+
+	for _, entry := range s.oversized {
+		resp.Oversized = append(resp.Oversized, proto.CloneOf(entry))
+	}
+	slices.SortFunc(resp.Oversized, func(a, b *inspector.OversizedItem) int {
+		return cmp.Or(cmp.Compare(b.MaxBytes, a.MaxBytes), cmp.Compare(a.Service, b.Service), cmp.Compare(a.Target, b.Target))
+	})
+	resp.Oversized = resp.Oversized[:min(len(resp.Oversized), maxReported)]
+}
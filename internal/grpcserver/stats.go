@@ -15,6 +15,8 @@ type DaemonStats struct {
 
 	activeReaders atomic.Int32
 	activeWriters atomic.Int32
+
+	sizes *sizeStats
 }
 
 func (d *DaemonStats) StartTime(t time.Time) {
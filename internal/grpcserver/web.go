@@ -0,0 +1,161 @@
+package grpcserver
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jimschubert/otel-relay/proto/inspector"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+// sseKeepAlive is how often an idle event stream is written to, so proxies don't time it out.
+const sseKeepAlive = 15 * time.Second
+
+// webEvent is a TelemetryEvent as sent to the web UI, with the request as OTLP/JSON.
+type webEvent struct {
+	// Signal is "traces", "metrics" or "logs".
+	Signal     string          `json:"signal"`
+	Violations []webViolation  `json:"violations,omitempty"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type webViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Target  string `json:"target,omitempty"`
+	Service string `json:"service,omitempty"`
+}
+
+func (s *Server) startWeb() error {
+	static, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		return fmt.Errorf("failed to load web UI: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /events", s.handleEvents)
+
+	ln, err := net.Listen("tcp", s.webAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for web UI on %s: %w", s.webAddr, err)
+	}
+
+	s.web = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := s.web.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("web UI server error: %v", err)
+		}
+	}()
+	log.Printf("Web UI available at %s", webURL(ln.Addr()))
+	return nil
+}
+
+// webURL is a browsable URL for the listener's address, using localhost when it listens on every interface.
+func webURL(addr net.Addr) string {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return "http://" + addr.String()
+	}
+	host := tcp.IP.String()
+	if tcp.IP == nil || tcp.IP.IsUnspecified() {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(tcp.Port))
+}
+
+// handleEvents streams every event to the browser using Server-Sent Events, until the client disconnects.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events := s.subscribe()
+	defer s.unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			payload, err := encodeWebEvent(event)
+			if err != nil {
+				log.Printf("Error encoding event for web UI: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// encodeWebEvent converts an event's request to OTLP/JSON. Note that protojson encodes trace and span IDs as base64
+// rather than hex, which the UI accounts for.
+func encodeWebEvent(event *inspector.TelemetryEvent) ([]byte, error) {
+	var req proto.Message
+	var signal string
+	switch event.Type {
+	case inspector.TelemetryType_TELEMETRY_TYPE_TRACE:
+		req, signal = &collectortrace.ExportTraceServiceRequest{}, "traces"
+	case inspector.TelemetryType_TELEMETRY_TYPE_METRIC:
+		req, signal = &collectormetrics.ExportMetricsServiceRequest{}, "metrics"
+	case inspector.TelemetryType_TELEMETRY_TYPE_LOG:
+		req, signal = &collectorlogs.ExportLogsServiceRequest{}, "logs"
+	default:
+		return nil, fmt.Errorf("unknown telemetry type %s", event.Type)
+	}
+
+	if err := proto.Unmarshal(event.Data, req); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", signal, err)
+	}
+	data, err := protojson.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", signal, err)
+	}
+
+	out := webEvent{Signal: signal, Data: data}
+	for _, v := range event.Violations {
+		out.Violations = append(out.Violations, webViolation{
+			Rule:    v.Rule,
+			Message: v.Message,
+			Target:  v.Target,
+			Service: v.Service,
+		})
+	}
+	return json.Marshal(out)
+}
@@ -6,9 +6,9 @@ import (
 	"log"
 	"time"
 
+	relay "github.com/jimschubert/otel-relay/inspector"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
@@ -16,18 +16,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-type Metrics struct {
-	EventsDropped   metric.Int64Counter
-	EventsWritten   metric.Int64Counter
-	GrpcTracesRecv  metric.Int64Counter
-	GrpcMetricsRecv metric.Int64Counter
-	GrpcLogsRecv    metric.Int64Counter
-	HttpTracesRecv  metric.Int64Counter
-	HttpMetricsRecv metric.Int64Counter
-	HttpLogsRecv    metric.Int64Counter
-}
-
-func Init(name, version string, endpoint string) (*Metrics, error) {
+func Init(name, version string, endpoint string) (*relay.Metrics, error) {
 	resDefault := resource.Default()
 	res, err := resource.Merge(resDefault,
 		resource.NewWithAttributes(
@@ -67,33 +56,5 @@ func Init(name, version string, endpoint string) (*Metrics, error) {
 
 	meter := otel.GetMeterProvider().Meter(name)
 
-	metrics := &Metrics{}
-
-	createCounter := func(name, desc string) (metric.Int64Counter, error) {
-		return meter.Int64Counter(name, metric.WithDescription(desc))
-	}
-
-	counters := []struct {
-		target *metric.Int64Counter
-		name   string
-		desc   string
-	}{
-		{&metrics.EventsDropped, "relay.events_dropped_total", "Total number of events dropped"},
-		{&metrics.EventsWritten, "relay.events_written_total", "Total number of events written to unix socket"},
-		{&metrics.GrpcTracesRecv, "relay.grpc_traces_received_total", "Total number of trace signals received via gRPC"},
-		{&metrics.GrpcMetricsRecv, "relay.grpc_metrics_received_total", "Total number of metric signals received via gRPC"},
-		{&metrics.GrpcLogsRecv, "relay.grpc_logs_received_total", "Total number of log signals received via gRPC"},
-		{&metrics.HttpTracesRecv, "relay.http_traces_received_total", "Total number of trace signals received via HTTP"},
-		{&metrics.HttpMetricsRecv, "relay.http_metrics_received_total", "Total number of metric signals received via HTTP"},
-		{&metrics.HttpLogsRecv, "relay.http_logs_received_total", "Total number of log signals received via HTTP"},
-	}
-
-	for _, c := range counters {
-		*c.target, err = createCounter(c.name, c.desc)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create counter %s: %w", c.name, err)
-		}
-	}
-
-	return metrics, nil
+	return relay.NewMetrics(meter)
 }
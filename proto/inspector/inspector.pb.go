@@ -231,6 +231,7 @@ type TelemetryEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
 	Type          TelemetryType          `protobuf:"varint,2,opt,name=type,proto3,enum=inspector.TelemetryType" json:"type,omitempty"`
+	Violations    []*Violation           `protobuf:"bytes,3,rep,name=violations,proto3" json:"violations,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -279,6 +280,81 @@ func (x *TelemetryEvent) GetType() TelemetryType {
 	return TelemetryType_TELEMETRY_TYPE_UNSPECIFIED
 }
 
+func (x *TelemetryEvent) GetViolations() []*Violation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+type Violation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Target        string                 `protobuf:"bytes,3,opt,name=target,proto3" json:"target,omitempty"`
+	Service       string                 `protobuf:"bytes,4,opt,name=service,proto3" json:"service,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Violation) Reset() {
+	*x = Violation{}
+	mi := &file_proto_inspector_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Violation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Violation) ProtoMessage() {}
+
+func (x *Violation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inspector_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Violation.ProtoReflect.Descriptor instead.
+func (*Violation) Descriptor() ([]byte, []int) {
+	return file_proto_inspector_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Violation) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *Violation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Violation) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *Violation) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
 type EmitResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -287,7 +363,7 @@ type EmitResponse struct {
 
 func (x *EmitResponse) Reset() {
 	*x = EmitResponse{}
-	mi := &file_proto_inspector_proto_msgTypes[4]
+	mi := &file_proto_inspector_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -299,7 +375,7 @@ func (x *EmitResponse) String() string {
 func (*EmitResponse) ProtoMessage() {}
 
 func (x *EmitResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_inspector_proto_msgTypes[4]
+	mi := &file_proto_inspector_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -312,18 +388,19 @@ func (x *EmitResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EmitResponse.ProtoReflect.Descriptor instead.
 func (*EmitResponse) Descriptor() ([]byte, []int) {
-	return file_proto_inspector_proto_rawDescGZIP(), []int{4}
+	return file_proto_inspector_proto_rawDescGZIP(), []int{5}
 }
 
 type StatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Breakdown     bool                   `protobuf:"varint,1,opt,name=breakdown,proto3" json:"breakdown,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *StatsRequest) Reset() {
 	*x = StatsRequest{}
-	mi := &file_proto_inspector_proto_msgTypes[5]
+	mi := &file_proto_inspector_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -335,7 +412,7 @@ func (x *StatsRequest) String() string {
 func (*StatsRequest) ProtoMessage() {}
 
 func (x *StatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_inspector_proto_msgTypes[5]
+	mi := &file_proto_inspector_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -348,7 +425,14 @@ func (x *StatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatsRequest.ProtoReflect.Descriptor instead.
 func (*StatsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_inspector_proto_rawDescGZIP(), []int{5}
+	return file_proto_inspector_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StatsRequest) GetBreakdown() bool {
+	if x != nil {
+		return x.Breakdown
+	}
+	return false
 }
 
 type StatsResponse struct {
@@ -360,13 +444,16 @@ type StatsResponse struct {
 	UptimeSeconds   int64                  `protobuf:"varint,5,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
 	ActiveReaders   int32                  `protobuf:"varint,6,opt,name=active_readers,json=activeReaders,proto3" json:"active_readers,omitempty"`
 	ActiveWriters   int32                  `protobuf:"varint,7,opt,name=active_writers,json=activeWriters,proto3" json:"active_writers,omitempty"`
+	Services        []*ServiceBytes        `protobuf:"bytes,8,rep,name=services,proto3" json:"services,omitempty"`
+	Attributes      []*AttributeBytes      `protobuf:"bytes,9,rep,name=attributes,proto3" json:"attributes,omitempty"`
+	Oversized       []*OversizedItem       `protobuf:"bytes,10,rep,name=oversized,proto3" json:"oversized,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *StatsResponse) Reset() {
 	*x = StatsResponse{}
-	mi := &file_proto_inspector_proto_msgTypes[6]
+	mi := &file_proto_inspector_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -378,7 +465,7 @@ func (x *StatsResponse) String() string {
 func (*StatsResponse) ProtoMessage() {}
 
 func (x *StatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_inspector_proto_msgTypes[6]
+	mi := &file_proto_inspector_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -391,7 +478,7 @@ func (x *StatsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
 func (*StatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_inspector_proto_rawDescGZIP(), []int{6}
+	return file_proto_inspector_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StatsResponse) GetTracesObserved() uint64 {
@@ -443,6 +530,343 @@ func (x *StatsResponse) GetActiveWriters() int32 {
 	return 0
 }
 
+func (x *StatsResponse) GetServices() []*ServiceBytes {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *StatsResponse) GetAttributes() []*AttributeBytes {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *StatsResponse) GetOversized() []*OversizedItem {
+	if x != nil {
+		return x.Oversized
+	}
+	return nil
+}
+
+type TraceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TraceId       []byte                 `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceRequest) Reset() {
+	*x = TraceRequest{}
+	mi := &file_proto_inspector_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceRequest) ProtoMessage() {}
+
+func (x *TraceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inspector_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceRequest.ProtoReflect.Descriptor instead.
+func (*TraceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_inspector_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TraceRequest) GetTraceId() []byte {
+	if x != nil {
+		return x.TraceId
+	}
+	return nil
+}
+
+type TraceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*TelemetryEvent      `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	History       int32                  `protobuf:"varint,2,opt,name=history,proto3" json:"history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceResponse) Reset() {
+	*x = TraceResponse{}
+	mi := &file_proto_inspector_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceResponse) ProtoMessage() {}
+
+func (x *TraceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inspector_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceResponse.ProtoReflect.Descriptor instead.
+func (*TraceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_inspector_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TraceResponse) GetEvents() []*TelemetryEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *TraceResponse) GetHistory() int32 {
+	if x != nil {
+		return x.History
+	}
+	return 0
+}
+
+type ServiceBytes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Type          TelemetryType          `protobuf:"varint,2,opt,name=type,proto3,enum=inspector.TelemetryType" json:"type,omitempty"`
+	Bytes         uint64                 `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Count         uint64                 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceBytes) Reset() {
+	*x = ServiceBytes{}
+	mi := &file_proto_inspector_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceBytes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceBytes) ProtoMessage() {}
+
+func (x *ServiceBytes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inspector_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceBytes.ProtoReflect.Descriptor instead.
+func (*ServiceBytes) Descriptor() ([]byte, []int) {
+	return file_proto_inspector_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ServiceBytes) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *ServiceBytes) GetType() TelemetryType {
+	if x != nil {
+		return x.Type
+	}
+	return TelemetryType_TELEMETRY_TYPE_UNSPECIFIED
+}
+
+func (x *ServiceBytes) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *ServiceBytes) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AttributeBytes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Bytes         uint64                 `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Count         uint64                 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	MaxBytes      uint64                 `protobuf:"varint,5,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttributeBytes) Reset() {
+	*x = AttributeBytes{}
+	mi := &file_proto_inspector_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttributeBytes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttributeBytes) ProtoMessage() {}
+
+func (x *AttributeBytes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inspector_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttributeBytes.ProtoReflect.Descriptor instead.
+func (*AttributeBytes) Descriptor() ([]byte, []int) {
+	return file_proto_inspector_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AttributeBytes) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *AttributeBytes) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *AttributeBytes) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *AttributeBytes) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *AttributeBytes) GetMaxBytes() uint64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+type OversizedItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Type          TelemetryType          `protobuf:"varint,2,opt,name=type,proto3,enum=inspector.TelemetryType" json:"type,omitempty"`
+	Target        string                 `protobuf:"bytes,3,opt,name=target,proto3" json:"target,omitempty"`
+	MaxBytes      uint64                 `protobuf:"varint,4,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	Count         uint64                 `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OversizedItem) Reset() {
+	*x = OversizedItem{}
+	mi := &file_proto_inspector_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OversizedItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OversizedItem) ProtoMessage() {}
+
+func (x *OversizedItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inspector_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OversizedItem.ProtoReflect.Descriptor instead.
+func (*OversizedItem) Descriptor() ([]byte, []int) {
+	return file_proto_inspector_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OversizedItem) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *OversizedItem) GetType() TelemetryType {
+	if x != nil {
+		return x.Type
+	}
+	return TelemetryType_TELEMETRY_TYPE_UNSPECIFIED
+}
+
+func (x *OversizedItem) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *OversizedItem) GetMaxBytes() uint64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+func (x *OversizedItem) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
 var File_proto_inspector_proto protoreflect.FileDescriptor
 
 const file_proto_inspector_proto_rawDesc = "" +
@@ -453,12 +877,21 @@ const file_proto_inspector_proto_rawDesc = "" +
 	"\rtoggle_output\x18\x02 \x01(\v2\x17.inspector.ToggleOutputH\x00R\ftoggleOutputB\x05\n" +
 	"\x03cmd\"\x0f\n" +
 	"\rToggleVerbose\"\x0e\n" +
-	"\fToggleOutput\"R\n" +
+	"\fToggleOutput\"\x88\x01\n" +
 	"\x0eTelemetryEvent\x12\x12\n" +
 	"\x04data\x18\x01 \x01(\fR\x04data\x12,\n" +
-	"\x04type\x18\x02 \x01(\x0e2\x18.inspector.TelemetryTypeR\x04type\"\x0e\n" +
-	"\fEmitResponse\"\x0e\n" +
-	"\fStatsRequest\"\xa4\x02\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x18.inspector.TelemetryTypeR\x04type\x124\n" +
+	"\n" +
+	"violations\x18\x03 \x03(\v2\x14.inspector.ViolationR\n" +
+	"violations\"k\n" +
+	"\tViolation\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06target\x18\x03 \x01(\tR\x06target\x12\x18\n" +
+	"\aservice\x18\x04 \x01(\tR\aservice\"\x0e\n" +
+	"\fEmitResponse\",\n" +
+	"\fStatsRequest\x12\x1c\n" +
+	"\tbreakdown\x18\x01 \x01(\bR\tbreakdown\"\xcc\x03\n" +
 	"\rStatsResponse\x12'\n" +
 	"\x0ftraces_observed\x18\x01 \x01(\x04R\x0etracesObserved\x12)\n" +
 	"\x10metrics_observed\x18\x02 \x01(\x04R\x0fmetricsObserved\x12#\n" +
@@ -466,16 +899,45 @@ const file_proto_inspector_proto_rawDesc = "" +
 	"\x0ebytes_observed\x18\x04 \x01(\x04R\rbytesObserved\x12%\n" +
 	"\x0euptime_seconds\x18\x05 \x01(\x03R\ruptimeSeconds\x12%\n" +
 	"\x0eactive_readers\x18\x06 \x01(\x05R\ractiveReaders\x12%\n" +
-	"\x0eactive_writers\x18\a \x01(\x05R\ractiveWriters*|\n" +
+	"\x0eactive_writers\x18\a \x01(\x05R\ractiveWriters\x123\n" +
+	"\bservices\x18\b \x03(\v2\x17.inspector.ServiceBytesR\bservices\x129\n" +
+	"\n" +
+	"attributes\x18\t \x03(\v2\x19.inspector.AttributeBytesR\n" +
+	"attributes\x126\n" +
+	"\toversized\x18\n" +
+	" \x03(\v2\x18.inspector.OversizedItemR\toversized\")\n" +
+	"\fTraceRequest\x12\x19\n" +
+	"\btrace_id\x18\x01 \x01(\fR\atraceId\"\\\n" +
+	"\rTraceResponse\x121\n" +
+	"\x06events\x18\x01 \x03(\v2\x19.inspector.TelemetryEventR\x06events\x12\x18\n" +
+	"\ahistory\x18\x02 \x01(\x05R\ahistory\"\x82\x01\n" +
+	"\fServiceBytes\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12,\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x18.inspector.TelemetryTypeR\x04type\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x04R\x05bytes\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x04R\x05count\"\x85\x01\n" +
+	"\x0eAttributeBytes\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x04R\x05bytes\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x04R\x05count\x12\x1b\n" +
+	"\tmax_bytes\x18\x05 \x01(\x04R\bmaxBytes\"\xa2\x01\n" +
+	"\rOversizedItem\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12,\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x18.inspector.TelemetryTypeR\x04type\x12\x16\n" +
+	"\x06target\x18\x03 \x01(\tR\x06target\x12\x1b\n" +
+	"\tmax_bytes\x18\x04 \x01(\x04R\bmaxBytes\x12\x14\n" +
+	"\x05count\x18\x05 \x01(\x04R\x05count*|\n" +
 	"\rTelemetryType\x12\x1e\n" +
 	"\x1aTELEMETRY_TYPE_UNSPECIFIED\x10\x00\x12\x18\n" +
 	"\x14TELEMETRY_TYPE_TRACE\x10\x01\x12\x19\n" +
 	"\x15TELEMETRY_TYPE_METRIC\x10\x02\x12\x16\n" +
-	"\x12TELEMETRY_TYPE_LOG\x10\x032\xca\x01\n" +
+	"\x12TELEMETRY_TYPE_LOG\x10\x032\x89\x02\n" +
 	"\x10InspectorService\x12;\n" +
 	"\x06Stream\x12\x12.inspector.Command\x1a\x19.inspector.TelemetryEvent(\x010\x01\x12:\n" +
 	"\x04Emit\x12\x19.inspector.TelemetryEvent\x1a\x17.inspector.EmitResponse\x12=\n" +
-	"\bGetStats\x12\x17.inspector.StatsRequest\x1a\x18.inspector.StatsResponseB3Z1github.com/jimschubert/otel-relay/proto/inspectorb\x06proto3"
+	"\bGetStats\x12\x17.inspector.StatsRequest\x1a\x18.inspector.StatsResponse\x12=\n" +
+	"\bGetTrace\x12\x17.inspector.TraceRequest\x1a\x18.inspector.TraceResponseB3Z1github.com/jimschubert/otel-relay/proto/inspectorb\x06proto3"
 
 var (
 	file_proto_inspector_proto_rawDescOnce sync.Once
@@ -490,32 +952,47 @@ func file_proto_inspector_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_inspector_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_inspector_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_inspector_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_proto_inspector_proto_goTypes = []any{
 	(TelemetryType)(0),     // 0: inspector.TelemetryType
 	(*Command)(nil),        // 1: inspector.Command
 	(*ToggleVerbose)(nil),  // 2: inspector.ToggleVerbose
 	(*ToggleOutput)(nil),   // 3: inspector.ToggleOutput
 	(*TelemetryEvent)(nil), // 4: inspector.TelemetryEvent
-	(*EmitResponse)(nil),   // 5: inspector.EmitResponse
-	(*StatsRequest)(nil),   // 6: inspector.StatsRequest
-	(*StatsResponse)(nil),  // 7: inspector.StatsResponse
+	(*Violation)(nil),      // 5: inspector.Violation
+	(*EmitResponse)(nil),   // 6: inspector.EmitResponse
+	(*StatsRequest)(nil),   // 7: inspector.StatsRequest
+	(*StatsResponse)(nil),  // 8: inspector.StatsResponse
+	(*TraceRequest)(nil),   // 9: inspector.TraceRequest
+	(*TraceResponse)(nil),  // 10: inspector.TraceResponse
+	(*ServiceBytes)(nil),   // 11: inspector.ServiceBytes
+	(*AttributeBytes)(nil), // 12: inspector.AttributeBytes
+	(*OversizedItem)(nil),  // 13: inspector.OversizedItem
 }
 var file_proto_inspector_proto_depIdxs = []int32{
-	2, // 0: inspector.Command.toggle_verbose:type_name -> inspector.ToggleVerbose
-	3, // 1: inspector.Command.toggle_output:type_name -> inspector.ToggleOutput
-	0, // 2: inspector.TelemetryEvent.type:type_name -> inspector.TelemetryType
-	1, // 3: inspector.InspectorService.Stream:input_type -> inspector.Command
-	4, // 4: inspector.InspectorService.Emit:input_type -> inspector.TelemetryEvent
-	6, // 5: inspector.InspectorService.GetStats:input_type -> inspector.StatsRequest
-	4, // 6: inspector.InspectorService.Stream:output_type -> inspector.TelemetryEvent
-	5, // 7: inspector.InspectorService.Emit:output_type -> inspector.EmitResponse
-	7, // 8: inspector.InspectorService.GetStats:output_type -> inspector.StatsResponse
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2,  // 0: inspector.Command.toggle_verbose:type_name -> inspector.ToggleVerbose
+	3,  // 1: inspector.Command.toggle_output:type_name -> inspector.ToggleOutput
+	0,  // 2: inspector.TelemetryEvent.type:type_name -> inspector.TelemetryType
+	5,  // 3: inspector.TelemetryEvent.violations:type_name -> inspector.Violation
+	11, // 4: inspector.StatsResponse.services:type_name -> inspector.ServiceBytes
+	12, // 5: inspector.StatsResponse.attributes:type_name -> inspector.AttributeBytes
+	13, // 6: inspector.StatsResponse.oversized:type_name -> inspector.OversizedItem
+	4,  // 7: inspector.TraceResponse.events:type_name -> inspector.TelemetryEvent
+	0,  // 8: inspector.ServiceBytes.type:type_name -> inspector.TelemetryType
+	0,  // 9: inspector.OversizedItem.type:type_name -> inspector.TelemetryType
+	1,  // 10: inspector.InspectorService.Stream:input_type -> inspector.Command
+	4,  // 11: inspector.InspectorService.Emit:input_type -> inspector.TelemetryEvent
+	7,  // 12: inspector.InspectorService.GetStats:input_type -> inspector.StatsRequest
+	9,  // 13: inspector.InspectorService.GetTrace:input_type -> inspector.TraceRequest
+	4,  // 14: inspector.InspectorService.Stream:output_type -> inspector.TelemetryEvent
+	6,  // 15: inspector.InspectorService.Emit:output_type -> inspector.EmitResponse
+	8,  // 16: inspector.InspectorService.GetStats:output_type -> inspector.StatsResponse
+	10, // 17: inspector.InspectorService.GetTrace:output_type -> inspector.TraceResponse
+	14, // [14:18] is the sub-list for method output_type
+	10, // [10:14] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_proto_inspector_proto_init() }
@@ -533,7 +1010,7 @@ func file_proto_inspector_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_inspector_proto_rawDesc), len(file_proto_inspector_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,7 @@ const (
 	InspectorService_Stream_FullMethodName   = "/inspector.InspectorService/Stream"
 	InspectorService_Emit_FullMethodName     = "/inspector.InspectorService/Emit"
 	InspectorService_GetStats_FullMethodName = "/inspector.InspectorService/GetStats"
+	InspectorService_GetTrace_FullMethodName = "/inspector.InspectorService/GetTrace"
 )
 
 // InspectorServiceClient is the client API for InspectorService service.
@@ -31,6 +32,7 @@ type InspectorServiceClient interface {
 	Stream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Command, TelemetryEvent], error)
 	Emit(ctx context.Context, in *TelemetryEvent, opts ...grpc.CallOption) (*EmitResponse, error)
 	GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+	GetTrace(ctx context.Context, in *TraceRequest, opts ...grpc.CallOption) (*TraceResponse, error)
 }
 
 type inspectorServiceClient struct {
@@ -74,6 +76,16 @@ func (c *inspectorServiceClient) GetStats(ctx context.Context, in *StatsRequest,
 	return out, nil
 }
 
+func (c *inspectorServiceClient) GetTrace(ctx context.Context, in *TraceRequest, opts ...grpc.CallOption) (*TraceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TraceResponse)
+	err := c.cc.Invoke(ctx, InspectorService_GetTrace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InspectorServiceServer is the server API for InspectorService service.
 // All implementations must embed UnimplementedInspectorServiceServer
 // for forward compatibility.
@@ -81,6 +93,7 @@ type InspectorServiceServer interface {
 	Stream(grpc.BidiStreamingServer[Command, TelemetryEvent]) error
 	Emit(context.Context, *TelemetryEvent) (*EmitResponse, error)
 	GetStats(context.Context, *StatsRequest) (*StatsResponse, error)
+	GetTrace(context.Context, *TraceRequest) (*TraceResponse, error)
 	mustEmbedUnimplementedInspectorServiceServer()
 }
 
@@ -100,6 +113,9 @@ func (UnimplementedInspectorServiceServer) Emit(context.Context, *TelemetryEvent
 func (UnimplementedInspectorServiceServer) GetStats(context.Context, *StatsRequest) (*StatsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetStats not implemented")
 }
+func (UnimplementedInspectorServiceServer) GetTrace(context.Context, *TraceRequest) (*TraceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTrace not implemented")
+}
 func (UnimplementedInspectorServiceServer) mustEmbedUnimplementedInspectorServiceServer() {}
 func (UnimplementedInspectorServiceServer) testEmbeddedByValue()                          {}
 
@@ -164,6 +180,24 @@ func _InspectorService_GetStats_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InspectorService_GetTrace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InspectorServiceServer).GetTrace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InspectorService_GetTrace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InspectorServiceServer).GetTrace(ctx, req.(*TraceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InspectorService_ServiceDesc is the grpc.ServiceDesc for InspectorService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -179,6 +213,10 @@ var InspectorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetStats",
 			Handler:    _InspectorService_GetStats_Handler,
 		},
+		{
+			MethodName: "GetTrace",
+			Handler:    _InspectorService_GetTrace_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
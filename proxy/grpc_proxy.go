@@ -10,7 +10,9 @@ import (
 	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 type OTLPProxy struct {
@@ -18,6 +20,7 @@ type OTLPProxy struct {
 	upstreamAddr string
 	inspector    *relay.Inspector
 	server       *grpc.Server
+	listener     net.Listener
 	upstreamConn *grpc.ClientConn
 
 	serveErr chan error
@@ -64,6 +67,7 @@ func (p *OTLPProxy) Start() error {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
+	p.listener = listener
 	p.server = grpc.NewServer()
 
 	collectortrace.RegisterTraceServiceServer(p.server, &traceServiceImpl{OTLPProxy: p})
@@ -78,6 +82,14 @@ func (p *OTLPProxy) Start() error {
 	return nil
 }
 
+// Addr returns the address the proxy is listening on, which differs from the configured address when listening on port 0.
+func (p *OTLPProxy) Addr() string {
+	if p.listener == nil {
+		return p.listenAddr
+	}
+	return p.listener.Addr().String()
+}
+
 func (p *OTLPProxy) Protocol() string {
 	return "grpc"
 }
@@ -102,7 +114,9 @@ func (p *OTLPProxy) Err() error {
 }
 
 func (t *traceServiceImpl) Export(ctx context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
-	t.inspector.InspectTraces(req)
+	if err := t.inspector.InspectTraces(ctx, req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if t.upstreamConn != nil && t.client == nil {
 		t.client = collectortrace.NewTraceServiceClient(t.upstreamConn)
 	}
@@ -113,7 +127,9 @@ func (t *traceServiceImpl) Export(ctx context.Context, req *collectortrace.Expor
 }
 
 func (m *metricsServiceImpl) Export(ctx context.Context, req *collectormetrics.ExportMetricsServiceRequest) (*collectormetrics.ExportMetricsServiceResponse, error) {
-	m.inspector.InspectMetrics(req)
+	if err := m.inspector.InspectMetrics(ctx, req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if m.upstreamConn != nil && m.client == nil {
 		m.client = collectormetrics.NewMetricsServiceClient(m.upstreamConn)
 	}
@@ -124,7 +140,9 @@ func (m *metricsServiceImpl) Export(ctx context.Context, req *collectormetrics.E
 }
 
 func (l *logsServiceImpl) Export(ctx context.Context, req *collectorlogs.ExportLogsServiceRequest) (*collectorlogs.ExportLogsServiceResponse, error) {
-	l.inspector.InspectLogs(req)
+	if err := l.inspector.InspectLogs(ctx, req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if l.upstreamConn != nil && l.client == nil {
 		l.client = collectorlogs.NewLogsServiceClient(l.upstreamConn)
 	}
@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	relay "github.com/jimschubert/otel-relay/inspector"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestOTLPProxyRejectInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []relay.Option
+		traceID  []byte
+		wantCode codes.Code
+	}{
+		{name: "valid", opts: []relay.Option{relay.WithRejectInvalid()}, traceID: bytes.Repeat([]byte{1}, 16), wantCode: codes.OK},
+		{name: "invalid and rejected", opts: []relay.Option{relay.WithRejectInvalid()}, traceID: make([]byte, 16), wantCode: codes.InvalidArgument},
+		{name: "invalid and only flagged", opts: []relay.Option{relay.WithValidation()}, traceID: make([]byte, 16), wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewOTLPProxy("127.0.0.1:0", "", relay.NewInspector(tt.opts...))
+			if err := p.Start(); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = p.Stop() })
+
+			conn, err := grpc.NewClient(p.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+
+			_, err = collectortrace.NewTraceServiceClient(conn).Export(context.Background(), &collectortrace.ExportTraceServiceRequest{
+				ResourceSpans: []*prototrace.ResourceSpans{{ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{{
+					TraceId:           tt.traceID,
+					SpanId:            bytes.Repeat([]byte{2}, 8),
+					Name:              "checkout",
+					StartTimeUnixNano: 1,
+					EndTimeUnixNano:   2,
+				}}}}}},
+			})
+			st := status.Convert(err)
+			if st.Code() != tt.wantCode {
+				t.Fatalf("got code %v (%v), want %v", st.Code(), err, tt.wantCode)
+			}
+			if tt.wantCode != codes.OK && !strings.Contains(st.Message(), "trace_id is all zeroes (otlp/trace-id)") {
+				t.Errorf("got message %q, want it to name the violation", st.Message())
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	relay "github.com/jimschubert/otel-relay/inspector"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type HTTPProxy struct {
+	listenAddr   string
+	upstreamAddr string
+	server       *http.Server
+	listener     net.Listener
+	inspector    *relay.Inspector
+	serveErr     chan error
+	doneChan     chan struct{}
+}
+
+func NewHTTPProxy(listenAddr, upstreamAddr string, insp *relay.Inspector) *HTTPProxy {
+	proxy := &HTTPProxy{
+		listenAddr:   listenAddr,
+		upstreamAddr: upstreamAddr,
+		inspector:    insp,
+		serveErr:     make(chan error, 1),
+		doneChan:     make(chan struct{}),
+	}
+
+	return proxy
+}
+
+func (p *HTTPProxy) Protocol() string {
+	return "http"
+}
+
+// Addr returns the address the proxy is listening on, which differs from the configured address when listening on port 0.
+func (p *HTTPProxy) Addr() string {
+	if p.listener == nil {
+		return p.listenAddr
+	}
+	return p.listener.Addr().String()
+}
+
+func (p *HTTPProxy) Start() error {
+	var reverseProxy *httputil.ReverseProxy
+	if p.upstreamAddr != "" {
+		upstreamURL, err := url.Parse(p.upstreamAddr)
+		if err != nil {
+			log.Printf("Error parsing upstream URL: %v", err)
+		}
+
+		reverseProxy = httputil.NewSingleHostReverseProxy(upstreamURL)
+	}
+
+	listener, err := net.Listen("tcp", p.listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	p.listener = listener
+
+	p.server = &http.Server{
+		Addr: p.listenAddr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := p.inspector.InspectHttpRequest(r); err != nil {
+				writeError(w, r, http.StatusBadRequest, codes.InvalidArgument, err)
+				return
+			}
+			if reverseProxy == nil {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			reverseProxy.ServeHTTP(w, r)
+		}),
+	}
+
+	go func() {
+		if err := p.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.serveErr <- err
+		}
+		close(p.serveErr)
+	}()
+
+	return nil
+}
+
+func (p *HTTPProxy) Err() error {
+	if p.serveErr == nil {
+		return nil
+	}
+	return <-p.serveErr
+}
+
+func (p *HTTPProxy) Stop() error {
+	var err error
+	if p.server != nil {
+		err = p.server.Close()
+	}
+	return err
+}
+
+// writeError responds with a google.rpc.Status body, encoded to match the request, as OTLP/HTTP requires for failures.
+func writeError(w http.ResponseWriter, r *http.Request, httpStatus int, code codes.Code, err error) {
+	var (
+		body        []byte
+		contentType string
+		marshalErr  error
+	)
+	st := status.New(code, err.Error()).Proto()
+	if strings.Contains(r.Header.Get("Content-Type"), "application/x-protobuf") {
+		contentType = "application/x-protobuf"
+		body, marshalErr = proto.Marshal(st)
+	} else {
+		contentType = "application/json"
+		body, marshalErr = protojson.Marshal(st)
+	}
+	if marshalErr != nil {
+		http.Error(w, err.Error(), httpStatus)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(httpStatus)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
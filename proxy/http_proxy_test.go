@@ -0,0 +1,75 @@
+package proxy
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	relay "github.com/jimschubert/otel-relay/inspector"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHTTPProxyRejectInvalid(t *testing.T) {
+	req := &collectortrace.ExportTraceServiceRequest{
+		ResourceSpans: []*prototrace.ResourceSpans{{ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{{
+			TraceId:           make([]byte, 16),
+			SpanId:            bytes.Repeat([]byte{2}, 8),
+			Name:              "checkout",
+			StartTimeUnixNano: 1,
+			EndTimeUnixNano:   2,
+		}}}}}},
+	}
+	tests := []struct {
+		name        string
+		contentType string
+		marshal     func(proto.Message) ([]byte, error)
+		unmarshal   func([]byte, proto.Message) error
+	}{
+		{name: "protobuf", contentType: "application/x-protobuf", marshal: proto.Marshal, unmarshal: proto.Unmarshal},
+		{name: "JSON", contentType: "application/json", marshal: protojson.Marshal, unmarshal: protojson.Unmarshal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewHTTPProxy("127.0.0.1:0", "", relay.NewInspector(relay.WithRejectInvalid()))
+			if err := p.Start(); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = p.Stop() })
+
+			body, err := tt.marshal(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.Post("http://"+p.Addr()+"/v1/traces", tt.contentType, bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("got status %d, want 400", resp.StatusCode)
+			}
+			if got := resp.Header.Get("Content-Type"); got != tt.contentType {
+				t.Errorf("got Content-Type %q, want %q", got, tt.contentType)
+			}
+
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var st spb.Status
+			if err := tt.unmarshal(respBody, &st); err != nil {
+				t.Fatalf("response is not a google.rpc.Status: %v", err)
+			}
+			if codes.Code(st.Code) != codes.InvalidArgument || !strings.Contains(st.Message, "trace_id is all zeroes") {
+				t.Errorf("got status %v, want InvalidArgument naming the violation", &st)
+			}
+		})
+	}
+}
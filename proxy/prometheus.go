@@ -0,0 +1,139 @@
+package proxy
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jimschubert/otel-relay/proto/prompb"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// staleNaN is the NaN Prometheus sends as a sample's value when its series has gone stale.
+const staleNaN = 0x7ff0000000000002
+
+// prometheusToOTLP converts remote write series to OTLP metrics. The job and instance labels become each resource's
+// service.name and service.instance.id, as in OpenTelemetry's Prometheus compatibility spec, and the other labels
+// become data point attributes. Counters, and the bucket, count and sum series of histograms and summaries, become
+// monotonic cumulative sums; everything else becomes a gauge. Without metadata, series ending in _total or _bucket
+// are treated as counters. Stale markers become data points flagged with no recorded value, and timestamps before the
+// epoch are left unset.
+func prometheusToOTLP(write *prompb.WriteRequest) *collectormetrics.ExportMetricsServiceRequest {
+	metadata := make(map[string]*prompb.MetricMetadata, len(write.Metadata))
+	for _, md := range write.Metadata {
+		metadata[md.MetricFamilyName] = md
+	}
+
+	req := &collectormetrics.ExportMetricsServiceRequest{}
+	resources := make(map[string]*protometrics.ScopeMetrics)
+	metrics := make(map[*protometrics.ScopeMetrics]map[string]*protometrics.Metric)
+
+	for _, series := range write.Timeseries {
+		var name, job, instance string
+		attrs := make([]*commonpb.KeyValue, 0, len(series.Labels))
+		for _, label := range series.Labels {
+			switch label.Name {
+			case "__name__":
+				name = label.Value
+			case "job":
+				job = label.Value
+			case "instance":
+				instance = label.Value
+			default:
+				attrs = append(attrs, stringAttribute(label.Name, label.Value))
+			}
+		}
+
+		key := job + "\x00" + instance
+		sm, ok := resources[key]
+		if !ok {
+			resource := &resourcepb.Resource{}
+			if job != "" {
+				resource.Attributes = append(resource.Attributes, stringAttribute("service.name", job))
+			}
+			if instance != "" {
+				resource.Attributes = append(resource.Attributes, stringAttribute("service.instance.id", instance))
+			}
+			sm = &protometrics.ScopeMetrics{}
+			req.ResourceMetrics = append(req.ResourceMetrics, &protometrics.ResourceMetrics{
+				Resource:     resource,
+				ScopeMetrics: []*protometrics.ScopeMetrics{sm},
+			})
+			resources[key] = sm
+			metrics[sm] = make(map[string]*protometrics.Metric)
+		}
+
+		metric, ok := metrics[sm][name]
+		if !ok {
+			metric = newPrometheusMetric(name, metadata)
+			metrics[sm][name] = metric
+			sm.Metrics = append(sm.Metrics, metric)
+		}
+
+		points := make([]*protometrics.NumberDataPoint, 0, len(series.Samples))
+		for _, sample := range series.Samples {
+			point := &protometrics.NumberDataPoint{
+				Attributes: attrs,
+				Value:      &protometrics.NumberDataPoint_AsDouble{AsDouble: sample.Value},
+			}
+			// a timestamp before the epoch can't be represented, so it's left unset
+			if sample.Timestamp > 0 {
+				point.TimeUnixNano = uint64(sample.Timestamp) * uint64(time.Millisecond)
+			}
+			if math.Float64bits(sample.Value) == staleNaN {
+				point.Flags = uint32(protometrics.DataPointFlags_DATA_POINT_FLAGS_NO_RECORDED_VALUE_MASK)
+			}
+			points = append(points, point)
+		}
+		if sum := metric.GetSum(); sum != nil {
+			sum.DataPoints = append(sum.DataPoints, points...)
+		} else {
+			metric.GetGauge().DataPoints = append(metric.GetGauge().DataPoints, points...)
+		}
+	}
+	return req
+}
+
+// newPrometheusMetric creates an empty sum or gauge for the series name, described by its family's metadata.
+func newPrometheusMetric(name string, metadata map[string]*prompb.MetricMetadata) *protometrics.Metric {
+	metric := &protometrics.Metric{Name: name}
+
+	md, ok := metadata[name]
+	suffix := ""
+	for _, s := range []string{"_bucket", "_count", "_sum", "_total"} {
+		if base, found := strings.CutSuffix(name, s); found {
+			suffix = s
+			if !ok {
+				md, ok = metadata[base]
+			}
+			break
+		}
+	}
+
+	counter := false
+	if ok {
+		metric.Description = md.Help
+		metric.Unit = md.Unit
+		switch md.Type {
+		case prompb.MetricMetadata_COUNTER:
+			counter = true
+		case prompb.MetricMetadata_HISTOGRAM, prompb.MetricMetadata_SUMMARY:
+			counter = suffix == "_bucket" || suffix == "_count" || suffix == "_sum"
+		}
+	} else {
+		counter = suffix == "_total" || suffix == "_bucket"
+	}
+
+	if counter {
+		metric.Data = &protometrics.Metric_Sum{Sum: &protometrics.Sum{
+			AggregationTemporality: protometrics.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			IsMonotonic:            true,
+		}}
+	} else {
+		metric.Data = &protometrics.Metric_Gauge{Gauge: &protometrics.Gauge{}}
+	}
+	return metric
+}
@@ -0,0 +1,181 @@
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/golang/snappy"
+	relay "github.com/jimschubert/otel-relay/inspector"
+	"github.com/jimschubert/otel-relay/proto/prompb"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// MaxRemoteWriteBytes bounds a remote write request's compressed body.
+	MaxRemoteWriteBytes = 16 << 20
+	// maxDecodedRemoteWriteBytes bounds the size a body's snappy header may declare, so a small body can't make the
+	// relay allocate an arbitrary amount of memory.
+	maxDecodedRemoteWriteBytes = 64 << 20
+)
+
+// PrometheusProxy accepts Prometheus remote write 1.0 requests on any path, inspects their samples as OTLP metrics,
+// and forwards the original payload to an upstream remote write endpoint.
+type PrometheusProxy struct {
+	listenAddr   string
+	upstreamAddr string
+	server       *http.Server
+	listener     net.Listener
+	inspector    *relay.Inspector
+	client       *http.Client
+	serveErr     chan error
+}
+
+func NewPrometheusProxy(listenAddr, upstreamAddr string, insp *relay.Inspector) *PrometheusProxy {
+	return &PrometheusProxy{
+		listenAddr:   listenAddr,
+		upstreamAddr: upstreamAddr,
+		inspector:    insp,
+		client:       &http.Client{},
+		serveErr:     make(chan error, 1),
+	}
+}
+
+func (p *PrometheusProxy) Protocol() string {
+	return "prometheus"
+}
+
+// Addr returns the address the proxy is listening on, which differs from the configured address when listening on port 0.
+func (p *PrometheusProxy) Addr() string {
+	if p.listener == nil {
+		return p.listenAddr
+	}
+	return p.listener.Addr().String()
+}
+
+func (p *PrometheusProxy) Start() error {
+	listener, err := net.Listen("tcp", p.listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	p.listener = listener
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxRemoteWriteBytes))
+		if err != nil {
+			if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+				http.Error(w, fmt.Sprintf("body exceeds %d bytes", maxErr.Limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, fmt.Sprintf("failed to read body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// the upstream may accept payloads this relay does not understand, such as remote write 2.0, so those are
+		// still forwarded
+		req, err := decodeRemoteWrite(body, r.Header.Get("Content-Type"))
+		if err != nil {
+			log.Printf("Error decoding remote write request: %v", err)
+			if p.upstreamAddr == "" {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else if err := p.inspector.InspectMetrics(r.Context(), req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if p.upstreamAddr == "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		p.forward(w, r, body)
+	})
+
+	p.server = &http.Server{
+		Addr:    p.listenAddr,
+		Handler: mux,
+	}
+
+	go func() {
+		if err := p.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.serveErr <- err
+		}
+		close(p.serveErr)
+	}()
+
+	return nil
+}
+
+// forward posts the original payload and headers to the upstream URL as-is, since remote write endpoints differ in
+// path (e.g. /api/v1/write or /api/v1/push), and relays its status and body.
+func (p *PrometheusProxy) forward(w http.ResponseWriter, r *http.Request, body []byte) {
+	upstreamReq, err := http.NewRequestWithContext(r.Context(), http.MethodPost, p.upstreamAddr, bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to create upstream request: %v", err), http.StatusInternalServerError)
+		return
+	}
+	upstreamReq.Header = r.Header.Clone()
+
+	resp, err := p.client.Do(upstreamReq)
+	if err != nil {
+		log.Printf("Error forwarding remote write request: %v", err)
+		http.Error(w, fmt.Sprintf("failed to forward to upstream: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+func (p *PrometheusProxy) Err() error {
+	if p.serveErr == nil {
+		return nil
+	}
+	return <-p.serveErr
+}
+
+func (p *PrometheusProxy) Stop() error {
+	var err error
+	if p.server != nil {
+		err = p.server.Close()
+	}
+	return err
+}
+
+// decodeRemoteWrite converts a snappy-compressed remote write 1.0 body to an OTLP metrics request.
+func decodeRemoteWrite(body []byte, contentType string) (*collectormetrics.ExportMetricsServiceRequest, error) {
+	if strings.Contains(contentType, "io.prometheus.write.v2.Request") {
+		return nil, errors.New("remote write 2.0 is not supported")
+	}
+
+	size, err := snappy.DecodedLen(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress body: %w", err)
+	}
+	if size > maxDecodedRemoteWriteBytes {
+		return nil, fmt.Errorf("decompressed body of %d bytes exceeds %d bytes", size, maxDecodedRemoteWriteBytes)
+	}
+	decoded, err := snappy.Decode(nil, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress body: %w", err)
+	}
+	var write prompb.WriteRequest
+	if err := proto.Unmarshal(decoded, &write); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal remote write request: %w", err)
+	}
+	return prometheusToOTLP(&write), nil
+}
@@ -0,0 +1,244 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/jimschubert/otel-relay/proto/prompb"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewPrometheusMetric(t *testing.T) {
+	metadata := map[string]*prompb.MetricMetadata{
+		"http_request_duration_seconds": {Type: prompb.MetricMetadata_HISTOGRAM, MetricFamilyName: "http_request_duration_seconds", Help: "Request latency.", Unit: "seconds"},
+		"rpc_duration_seconds":          {Type: prompb.MetricMetadata_SUMMARY, MetricFamilyName: "rpc_duration_seconds"},
+		"queue_depth":                   {Type: prompb.MetricMetadata_GAUGE, MetricFamilyName: "queue_depth", Help: "Items waiting."},
+		"jobs_processed":                {Type: prompb.MetricMetadata_COUNTER, MetricFamilyName: "jobs_processed"},
+		"errors_total":                  {Type: prompb.MetricMetadata_GAUGE, MetricFamilyName: "errors_total"},
+	}
+	tests := []struct {
+		name            string
+		series          string
+		wantSum         bool
+		wantDescription string
+		wantUnit        string
+	}{
+		{name: "_total without metadata is a counter", series: "requests_total", wantSum: true},
+		{name: "_bucket without metadata is a counter", series: "latency_bucket", wantSum: true},
+		{name: "_count without metadata is a gauge", series: "latency_count"},
+		{name: "plain name without metadata is a gauge", series: "temperature_celsius"},
+		{name: "histogram bucket by base name", series: "http_request_duration_seconds_bucket", wantSum: true, wantDescription: "Request latency.", wantUnit: "seconds"},
+		{name: "histogram sum by base name", series: "http_request_duration_seconds_sum", wantSum: true, wantDescription: "Request latency.", wantUnit: "seconds"},
+		{name: "histogram count by base name", series: "http_request_duration_seconds_count", wantSum: true, wantDescription: "Request latency.", wantUnit: "seconds"},
+		{name: "summary quantile is a gauge", series: "rpc_duration_seconds"},
+		{name: "summary count is a counter", series: "rpc_duration_seconds_count", wantSum: true},
+		{name: "gauge metadata", series: "queue_depth", wantDescription: "Items waiting."},
+		{name: "counter metadata without suffix", series: "jobs_processed", wantSum: true},
+		{name: "exact metadata overrides the _total suffix", series: "errors_total"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := newPrometheusMetric(tt.series, metadata)
+			if metric.Name != tt.series {
+				t.Errorf("got name %q, want %q", metric.Name, tt.series)
+			}
+			if sum := metric.GetSum(); tt.wantSum {
+				if sum == nil {
+					t.Fatalf("got %T, want a sum", metric.Data)
+				}
+				if !sum.IsMonotonic || sum.AggregationTemporality != protometrics.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE {
+					t.Errorf("got monotonic=%v temporality=%v, want a monotonic cumulative sum", sum.IsMonotonic, sum.AggregationTemporality)
+				}
+			} else if metric.GetGauge() == nil {
+				t.Fatalf("got %T, want a gauge", metric.Data)
+			}
+			if metric.Description != tt.wantDescription || metric.Unit != tt.wantUnit {
+				t.Errorf("got description %q unit %q, want %q %q", metric.Description, metric.Unit, tt.wantDescription, tt.wantUnit)
+			}
+		})
+	}
+}
+
+func TestPrometheusToOTLP(t *testing.T) {
+	labels := func(pairs ...string) []*prompb.Label {
+		var out []*prompb.Label
+		for i := 0; i < len(pairs); i += 2 {
+			out = append(out, &prompb.Label{Name: pairs[i], Value: pairs[i+1]})
+		}
+		return out
+	}
+	tests := []struct {
+		name  string
+		write *prompb.WriteRequest
+		check func(t *testing.T, rms []*protometrics.ResourceMetrics)
+	}{
+		{
+			name: "job and instance become resource attributes",
+			write: &prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{{
+				Labels:  labels("__name__", "up", "instance", "localhost:9090", "job", "prometheus", "zone", "a"),
+				Samples: []*prompb.Sample{{Value: 1, Timestamp: 1700000000123}},
+			}}},
+			check: func(t *testing.T, rms []*protometrics.ResourceMetrics) {
+				if len(rms) != 1 {
+					t.Fatalf("got %d resources, want 1", len(rms))
+				}
+				wantAttributes(t, rms[0].Resource.Attributes, "service.name=prometheus", "service.instance.id=localhost:9090")
+				point := onlyPoint(t, rms[0].ScopeMetrics[0].Metrics[0])
+				wantAttributes(t, point.Attributes, "zone=a")
+				if point.TimeUnixNano != 1700000000123000000 {
+					t.Errorf("got time %d, want 1700000000123000000", point.TimeUnixNano)
+				}
+				if point.GetAsDouble() != 1 {
+					t.Errorf("got value %v, want 1", point.GetAsDouble())
+				}
+			},
+		},
+		{
+			name: "series are grouped by resource and metric name",
+			write: &prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{
+				{Labels: labels("__name__", "requests_total", "code", "200", "job", "api"), Samples: []*prompb.Sample{{Value: 10, Timestamp: 1}}},
+				{Labels: labels("__name__", "requests_total", "code", "500", "job", "api"), Samples: []*prompb.Sample{{Value: 2, Timestamp: 1}}},
+				{Labels: labels("__name__", "requests_total", "code", "200", "job", "web"), Samples: []*prompb.Sample{{Value: 7, Timestamp: 1}}},
+			}},
+			check: func(t *testing.T, rms []*protometrics.ResourceMetrics) {
+				if len(rms) != 2 {
+					t.Fatalf("got %d resources, want 2", len(rms))
+				}
+				metrics := rms[0].ScopeMetrics[0].Metrics
+				if len(metrics) != 1 || len(metrics[0].GetSum().GetDataPoints()) != 2 {
+					t.Fatalf("got %d metrics, want 1 sum with 2 data points", len(metrics))
+				}
+				if got := len(rms[1].ScopeMetrics[0].Metrics[0].GetSum().GetDataPoints()); got != 1 {
+					t.Errorf("got %d data points for the second resource, want 1", got)
+				}
+			},
+		},
+		{
+			name: "stale marker has no recorded value",
+			write: &prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{{
+				Labels:  labels("__name__", "up", "job", "api"),
+				Samples: []*prompb.Sample{{Value: math.Float64frombits(staleNaN), Timestamp: 1}},
+			}}},
+			check: func(t *testing.T, rms []*protometrics.ResourceMetrics) {
+				point := onlyPoint(t, rms[0].ScopeMetrics[0].Metrics[0])
+				if point.Flags != uint32(protometrics.DataPointFlags_DATA_POINT_FLAGS_NO_RECORDED_VALUE_MASK) {
+					t.Errorf("got flags %d, want no recorded value", point.Flags)
+				}
+			},
+		},
+		{
+			name: "ordinary NaN is a recorded value",
+			write: &prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{{
+				Labels:  labels("__name__", "up", "job", "api"),
+				Samples: []*prompb.Sample{{Value: math.NaN(), Timestamp: 1}},
+			}}},
+			check: func(t *testing.T, rms []*protometrics.ResourceMetrics) {
+				if point := onlyPoint(t, rms[0].ScopeMetrics[0].Metrics[0]); point.Flags != 0 {
+					t.Errorf("got flags %d, want 0", point.Flags)
+				}
+			},
+		},
+		{
+			name: "negative timestamp is left unset",
+			write: &prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{{
+				Labels:  labels("__name__", "up"),
+				Samples: []*prompb.Sample{{Value: 1, Timestamp: -1}},
+			}}},
+			check: func(t *testing.T, rms []*protometrics.ResourceMetrics) {
+				if len(rms[0].Resource.Attributes) != 0 {
+					t.Errorf("got resource attributes %v, want none", rms[0].Resource.Attributes)
+				}
+				if point := onlyPoint(t, rms[0].ScopeMetrics[0].Metrics[0]); point.TimeUnixNano != 0 {
+					t.Errorf("got time %d, want 0", point.TimeUnixNano)
+				}
+			},
+		},
+		{
+			name: "histogram metadata describes each series",
+			write: &prompb.WriteRequest{
+				Metadata: []*prompb.MetricMetadata{{Type: prompb.MetricMetadata_HISTOGRAM, MetricFamilyName: "latency_seconds", Help: "Latency."}},
+				Timeseries: []*prompb.TimeSeries{
+					{Labels: labels("__name__", "latency_seconds_bucket", "le", "0.5"), Samples: []*prompb.Sample{{Value: 3, Timestamp: 1}}},
+					{Labels: labels("__name__", "latency_seconds_count"), Samples: []*prompb.Sample{{Value: 4, Timestamp: 1}}},
+					{Labels: labels("__name__", "latency_seconds_sum"), Samples: []*prompb.Sample{{Value: 1.5, Timestamp: 1}}},
+				},
+			},
+			check: func(t *testing.T, rms []*protometrics.ResourceMetrics) {
+				metrics := rms[0].ScopeMetrics[0].Metrics
+				if len(metrics) != 3 {
+					t.Fatalf("got %d metrics, want 3", len(metrics))
+				}
+				for _, metric := range metrics {
+					if metric.GetSum() == nil || metric.Description != "Latency." {
+						t.Errorf("%s: got %T with description %q, want a described sum", metric.Name, metric.Data, metric.Description)
+					}
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, prometheusToOTLP(tt.write).ResourceMetrics)
+		})
+	}
+}
+
+func TestDecodeRemoteWrite(t *testing.T) {
+	write := &prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{{
+		Labels:  []*prompb.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "api"}},
+		Samples: []*prompb.Sample{{Value: 1, Timestamp: 1}},
+	}}}
+	payload, err := proto.Marshal(write)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a snappy block starts with its decoded length as a uvarint, which is all DecodedLen reads
+	oversized := binary.AppendUvarint(nil, maxDecodedRemoteWriteBytes+1)
+
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+		wantErr     string
+	}{
+		{name: "remote write 1.0", body: snappy.Encode(nil, payload), contentType: "application/x-protobuf"},
+		{name: "remote write 1.0 with proto parameter", body: snappy.Encode(nil, payload), contentType: "application/x-protobuf;proto=prometheus.WriteRequest"},
+		{name: "remote write 2.0", body: snappy.Encode(nil, payload), contentType: "application/x-protobuf;proto=io.prometheus.write.v2.Request", wantErr: "remote write 2.0 is not supported"},
+		{name: "not snappy", body: []byte("not snappy"), wantErr: "failed to decompress body"},
+		{name: "not a write request", body: snappy.Encode(nil, []byte{0xff}), wantErr: "failed to unmarshal remote write request"},
+		{name: "declared length over the limit", body: oversized, wantErr: "exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := decodeRemoteWrite(tt.body, tt.contentType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := req.ResourceMetrics[0].ScopeMetrics[0].Metrics[0].Name; got != "up" {
+				t.Errorf("got metric %q, want up", got)
+			}
+		})
+	}
+}
+
+func onlyPoint(t *testing.T, metric *protometrics.Metric) *protometrics.NumberDataPoint {
+	t.Helper()
+	points := metric.GetGauge().GetDataPoints()
+	if sum := metric.GetSum(); sum != nil {
+		points = sum.DataPoints
+	}
+	if len(points) != 1 {
+		t.Fatalf("got %d data points, want 1", len(points))
+	}
+	return points[0]
+}
@@ -0,0 +1,187 @@
+package proxy
+
+import (
+	"cmp"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// zipkinSpan is a span in the Zipkin v2 JSON format, see https://zipkin.io/zipkin-api/#/default/post_spans
+type zipkinSpan struct {
+	TraceID        string             `json:"traceId"`
+	ID             string             `json:"id"`
+	ParentID       string             `json:"parentId"`
+	Name           string             `json:"name"`
+	Kind           string             `json:"kind"`
+	Timestamp      uint64             `json:"timestamp"`
+	Duration       uint64             `json:"duration"`
+	LocalEndpoint  *zipkinEndpoint    `json:"localEndpoint"`
+	RemoteEndpoint *zipkinEndpoint    `json:"remoteEndpoint"`
+	Annotations    []zipkinAnnotation `json:"annotations"`
+	Tags           map[string]string  `json:"tags"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+	IPv4        string `json:"ipv4"`
+	IPv6        string `json:"ipv6"`
+	Port        int    `json:"port"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp uint64 `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+var zipkinKinds = map[string]prototrace.Span_SpanKind{
+	"CLIENT":   prototrace.Span_SPAN_KIND_CLIENT,
+	"SERVER":   prototrace.Span_SPAN_KIND_SERVER,
+	"PRODUCER": prototrace.Span_SPAN_KIND_PRODUCER,
+	"CONSUMER": prototrace.Span_SPAN_KIND_CONSUMER,
+}
+
+// zipkinToOTLP converts Zipkin spans the way the OpenTelemetry Collector's Zipkin receiver does: one resource per
+// local service, times in nanoseconds, tags as string attributes, and annotations as events. The error,
+// otel.status_code and otel.status_description tags become the span's status, and otel.scope.name and
+// otel.scope.version its instrumentation scope.
+//
+// Zipkin allows spans without a timestamp, such as the remote half of a shared span, but an OTLP span without a start
+// time would start at the Unix epoch, so those are skipped and counted instead.
+func zipkinToOTLP(spans []zipkinSpan) (req *collectortrace.ExportTraceServiceRequest, skipped int, err error) {
+	req = &collectortrace.ExportTraceServiceRequest{}
+	resources := make(map[string]*prototrace.ResourceSpans)
+	scopes := make(map[*prototrace.ResourceSpans]map[string]*prototrace.ScopeSpans)
+
+	for idx, zs := range spans {
+		if zs.Timestamp == 0 {
+			skipped++
+			continue
+		}
+		span, err := zipkinSpanToOTLP(zs)
+		if err != nil {
+			return nil, 0, fmt.Errorf("span %d: %w", idx, err)
+		}
+
+		service := ""
+		if zs.LocalEndpoint != nil {
+			service = zs.LocalEndpoint.ServiceName
+		}
+		rs, ok := resources[service]
+		if !ok {
+			rs = &prototrace.ResourceSpans{Resource: &resourcepb.Resource{}}
+			if service != "" {
+				rs.Resource.Attributes = append(rs.Resource.Attributes, stringAttribute("service.name", service))
+			}
+			resources[service] = rs
+			scopes[rs] = make(map[string]*prototrace.ScopeSpans)
+			req.ResourceSpans = append(req.ResourceSpans, rs)
+		}
+
+		name, version := zs.Tags["otel.scope.name"], zs.Tags["otel.scope.version"]
+		key := name + "@" + version
+		ss, ok := scopes[rs][key]
+		if !ok {
+			ss = &prototrace.ScopeSpans{}
+			if name != "" || version != "" {
+				ss.Scope = &commonpb.InstrumentationScope{Name: name, Version: version}
+			}
+			scopes[rs][key] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+		ss.Spans = append(ss.Spans, span)
+	}
+	return req, skipped, nil
+}
+
+func zipkinSpanToOTLP(zs zipkinSpan) (*prototrace.Span, error) {
+	traceID, err := zipkinID("traceId", zs.TraceID, 16)
+	if err != nil {
+		return nil, err
+	}
+	spanID, err := zipkinID("id", zs.ID, 8)
+	if err != nil {
+		return nil, err
+	}
+	span := &prototrace.Span{
+		TraceId:           traceID,
+		SpanId:            spanID,
+		Name:              zs.Name,
+		Kind:              zipkinKinds[strings.ToUpper(zs.Kind)],
+		StartTimeUnixNano: zs.Timestamp * 1000,
+		EndTimeUnixNano:   (zs.Timestamp + zs.Duration) * 1000,
+		Status:            &prototrace.Status{},
+	}
+	if span.Kind == prototrace.Span_SPAN_KIND_UNSPECIFIED {
+		span.Kind = prototrace.Span_SPAN_KIND_INTERNAL
+	}
+	if zs.ParentID != "" {
+		if span.ParentSpanId, err = zipkinID("parentId", zs.ParentID, 8); err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range zs.Tags {
+		switch key {
+		case "otel.scope.name", "otel.scope.version", "otel.status_description":
+		case "otel.status_code":
+			switch value {
+			case "ERROR":
+				span.Status.Code = prototrace.Status_STATUS_CODE_ERROR
+			case "OK":
+				span.Status.Code = prototrace.Status_STATUS_CODE_OK
+			}
+		case "error":
+			span.Status.Code = prototrace.Status_STATUS_CODE_ERROR
+			if value != "true" && value != "" {
+				span.Status.Message = value
+			}
+		default:
+			span.Attributes = append(span.Attributes, stringAttribute(key, value))
+		}
+	}
+	if description, ok := zs.Tags["otel.status_description"]; ok && span.Status.Code == prototrace.Status_STATUS_CODE_ERROR {
+		span.Status.Message = description
+	}
+	// tags are a map, so sort the attributes for stable output
+	slices.SortFunc(span.Attributes, func(x, y *commonpb.KeyValue) int { return strings.Compare(x.Key, y.Key) })
+
+	if remote := zs.RemoteEndpoint; remote != nil {
+		if remote.ServiceName != "" {
+			span.Attributes = append(span.Attributes, stringAttribute("peer.service", remote.ServiceName))
+		}
+		if address := cmp.Or(remote.IPv4, remote.IPv6); address != "" {
+			span.Attributes = append(span.Attributes, stringAttribute("network.peer.address", address))
+		}
+		if remote.Port != 0 {
+			span.Attributes = append(span.Attributes, &commonpb.KeyValue{Key: "network.peer.port", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(remote.Port)}}})
+		}
+	}
+
+	for _, annotation := range zs.Annotations {
+		span.Events = append(span.Events, &prototrace.Span_Event{TimeUnixNano: annotation.Timestamp * 1000, Name: annotation.Value})
+	}
+	return span, nil
+}
+
+// zipkinID decodes a hex ID, left-padding it to size bytes, since Zipkin allows 64-bit trace IDs.
+func zipkinID(field, id string, size int) ([]byte, error) {
+	if len(id) == 0 || len(id) > size*2 {
+		return nil, fmt.Errorf("%s must be 1 to %d hex characters, got %q", field, size*2, id)
+	}
+	decoded, err := hex.DecodeString(strings.Repeat("0", size*2-len(id)) + id)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q is not hex", field, id)
+	}
+	return decoded, nil
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
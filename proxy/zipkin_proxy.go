@@ -0,0 +1,229 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	relay "github.com/jimschubert/otel-relay/inspector"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ZipkinSpansPath is where Zipkin reporters send spans.
+	ZipkinSpansPath = "/api/v2/spans"
+
+	// MaxZipkinBytes bounds a Zipkin request's body, before decompression.
+	MaxZipkinBytes = 16 << 20
+	// maxDecodedZipkinBytes bounds a gzipped body once decompressed, so a small body can't make the relay allocate an
+	// arbitrary amount of memory.
+	maxDecodedZipkinBytes = 64 << 20
+)
+
+// ZipkinProxy accepts Zipkin v2 JSON spans, inspects them as OTLP traces, and forwards them to an upstream Zipkin
+// server, or to an upstream OTLP/HTTP collector with WithOTLPUpstream.
+type ZipkinProxy struct {
+	listenAddr   string
+	upstreamAddr string
+	otlpUpstream bool
+	server       *http.Server
+	listener     net.Listener
+	inspector    *relay.Inspector
+	client       *http.Client
+	serveErr     chan error
+}
+
+type ZipkinOption func(*ZipkinProxy)
+
+// WithOTLPUpstream forwards the converted spans as OTLP/HTTP protobuf to the upstream's /v1/traces, instead of
+// forwarding the original Zipkin payload.
+func WithOTLPUpstream() ZipkinOption {
+	return func(p *ZipkinProxy) {
+		p.otlpUpstream = true
+	}
+}
+
+func NewZipkinProxy(listenAddr, upstreamAddr string, insp *relay.Inspector, opts ...ZipkinOption) *ZipkinProxy {
+	proxy := &ZipkinProxy{
+		listenAddr:   listenAddr,
+		upstreamAddr: upstreamAddr,
+		inspector:    insp,
+		client:       &http.Client{},
+		serveErr:     make(chan error, 1),
+	}
+	for _, opt := range opts {
+		opt(proxy)
+	}
+	return proxy
+}
+
+func (p *ZipkinProxy) Protocol() string {
+	return "zipkin"
+}
+
+// Addr returns the address the proxy is listening on, which differs from the configured address when listening on port 0.
+func (p *ZipkinProxy) Addr() string {
+	if p.listener == nil {
+		return p.listenAddr
+	}
+	return p.listener.Addr().String()
+}
+
+func (p *ZipkinProxy) Start() error {
+	var upstreamURL *url.URL
+	if p.upstreamAddr != "" {
+		var err error
+		if upstreamURL, err = url.Parse(p.upstreamAddr); err != nil {
+			return fmt.Errorf("failed to parse upstream URL: %w", err)
+		}
+	}
+
+	var reverseProxy *httputil.ReverseProxy
+	if upstreamURL != nil && !p.otlpUpstream {
+		reverseProxy = httputil.NewSingleHostReverseProxy(upstreamURL)
+	}
+
+	listener, err := net.Listen("tcp", p.listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	p.listener = listener
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+ZipkinSpansPath, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxZipkinBytes))
+		if err != nil {
+			if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+				http.Error(w, fmt.Sprintf("body exceeds %d bytes", maxErr.Limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, fmt.Sprintf("failed to read body: %v", err), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// an upstream Zipkin server may accept payloads this relay does not understand, so those are still forwarded
+		req, err := decodeZipkin(body, r.Header.Get("Content-Encoding"))
+		switch {
+		case err != nil:
+			log.Printf("Error decoding Zipkin spans: %v", err)
+			if reverseProxy == nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		case len(req.ResourceSpans) > 0:
+			if err := p.inspector.InspectTraces(r.Context(), req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		switch {
+		case reverseProxy != nil:
+			reverseProxy.ServeHTTP(w, r)
+		case upstreamURL != nil:
+			p.forwardOTLP(w, r, upstreamURL, req)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	})
+
+	p.server = &http.Server{
+		Addr:    p.listenAddr,
+		Handler: mux,
+	}
+
+	go func() {
+		if err := p.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.serveErr <- err
+		}
+		close(p.serveErr)
+	}()
+
+	return nil
+}
+
+// forwardOTLP posts the converted request to the upstream collector, and relays its status and body.
+func (p *ZipkinProxy) forwardOTLP(w http.ResponseWriter, r *http.Request, upstreamURL *url.URL, req proto.Message) {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to marshal OTLP request: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	upstreamReq, err := http.NewRequestWithContext(r.Context(), http.MethodPost, upstreamURL.JoinPath("/v1/traces").String(), bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to create upstream request: %v", err), http.StatusInternalServerError)
+		return
+	}
+	upstreamReq.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := p.client.Do(upstreamReq)
+	if err != nil {
+		log.Printf("Error forwarding Zipkin spans as OTLP: %v", err)
+		http.Error(w, fmt.Sprintf("failed to forward to upstream: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+func (p *ZipkinProxy) Err() error {
+	if p.serveErr == nil {
+		return nil
+	}
+	return <-p.serveErr
+}
+
+func (p *ZipkinProxy) Stop() error {
+	var err error
+	if p.server != nil {
+		err = p.server.Close()
+	}
+	return err
+}
+
+// decodeZipkin converts a Zipkin v2 JSON body, optionally gzipped, to an OTLP trace request.
+func decodeZipkin(body []byte, encoding string) (*collectortrace.ExportTraceServiceRequest, error) {
+	if strings.EqualFold(encoding, "gzip") {
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress body: %w", err)
+		}
+		defer reader.Close()
+		if body, err = io.ReadAll(io.LimitReader(reader, maxDecodedZipkinBytes+1)); err != nil {
+			return nil, fmt.Errorf("failed to decompress body: %w", err)
+		}
+		if len(body) > maxDecodedZipkinBytes {
+			return nil, fmt.Errorf("decompressed body exceeds %d bytes", maxDecodedZipkinBytes)
+		}
+	}
+
+	var spans []zipkinSpan
+	if err := json.Unmarshal(body, &spans); err != nil {
+		return nil, fmt.Errorf("failed to decode Zipkin v2 JSON: %w", err)
+	}
+	req, skipped, err := zipkinToOTLP(spans)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Zipkin span: %w", err)
+	}
+	if skipped > 0 {
+		log.Printf("Skipped %d of %d Zipkin spans without a timestamp", skipped, len(spans))
+	}
+	return req, nil
+}
@@ -0,0 +1,278 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// backendSpan is the example span from the Zipkin v2 API documentation, with a 64-bit trace ID.
+const backendSpan = `[{
+  "id": "352bff9a74ca9ad2",
+  "traceId": "5af7183fb1d4cf5f",
+  "parentId": "6b221d5bc9e6496c",
+  "name": "get /api",
+  "timestamp": 1556604172355737,
+  "duration": 1431,
+  "kind": "SERVER",
+  "localEndpoint": {"serviceName": "backend", "ipv4": "192.168.99.1", "port": 3306},
+  "remoteEndpoint": {"ipv4": "172.19.0.2", "port": 58648},
+  "tags": {"http.method": "GET", "http.path": "/api"}
+}]`
+
+func TestZipkinID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		size    int
+		want    string
+		wantErr string
+	}{
+		{name: "64-bit trace ID is left-padded", id: "5af7183fb1d4cf5f", size: 16, want: "00000000000000005af7183fb1d4cf5f"},
+		{name: "128-bit trace ID", id: "463ac35c9f6413ad48485a3953bb6124", size: 16, want: "463ac35c9f6413ad48485a3953bb6124"},
+		{name: "span ID", id: "352bff9a74ca9ad2", size: 8, want: "352bff9a74ca9ad2"},
+		{name: "short odd-length ID", id: "abc", size: 8, want: "0000000000000abc"},
+		{name: "empty", id: "", size: 8, wantErr: "must be 1 to 16 hex characters"},
+		{name: "too long", id: "463ac35c9f6413ad48485a3953bb61240", size: 16, wantErr: "must be 1 to 32 hex characters"},
+		{name: "bad hex", id: "352bff9a74ca9adz", size: 8, wantErr: "is not hex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := zipkinID("id", tt.id, tt.size)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("got %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeZipkin(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		gzip     bool
+		encoding string
+		wantErr  string
+		check    func(t *testing.T, req *collectortrace.ExportTraceServiceRequest)
+	}{
+		{
+			name: "documentation example",
+			body: backendSpan,
+			check: func(t *testing.T, req *collectortrace.ExportTraceServiceRequest) {
+				span := onlySpan(t, req)
+				if service := req.ResourceSpans[0].Resource.Attributes[0]; service.Key != "service.name" || service.Value.GetStringValue() != "backend" {
+					t.Errorf("got resource attribute %v, want service.name backend", service)
+				}
+				if hex.EncodeToString(span.TraceId) != "00000000000000005af7183fb1d4cf5f" {
+					t.Errorf("got trace ID %x", span.TraceId)
+				}
+				if hex.EncodeToString(span.ParentSpanId) != "6b221d5bc9e6496c" {
+					t.Errorf("got parent span ID %x", span.ParentSpanId)
+				}
+				if span.Kind != prototrace.Span_SPAN_KIND_SERVER {
+					t.Errorf("got kind %v, want SERVER", span.Kind)
+				}
+				if span.StartTimeUnixNano != 1556604172355737000 || span.EndTimeUnixNano != 1556604172357168000 {
+					t.Errorf("got start %d, end %d", span.StartTimeUnixNano, span.EndTimeUnixNano)
+				}
+				wantAttributes(t, span.Attributes, "http.method=GET", "http.path=/api", "network.peer.address=172.19.0.2", "network.peer.port=58648")
+			},
+		},
+		{
+			name: "gzipped",
+			body: backendSpan,
+			gzip: true,
+			check: func(t *testing.T, req *collectortrace.ExportTraceServiceRequest) {
+				if span := onlySpan(t, req); span.Name != "get /api" {
+					t.Errorf("got name %q, want get /api", span.Name)
+				}
+			},
+		},
+		{
+			name: "client span with 128-bit trace ID, remote service and annotations",
+			body: `[{"traceId":"463ac35c9f6413ad48485a3953bb6124","id":"a2fb4a1d1a96d312","name":"query","kind":"CLIENT",
+				"timestamp":1556604172355737,"duration":50,"localEndpoint":{"serviceName":"api"},
+				"remoteEndpoint":{"serviceName":"mysql","ipv6":"::1","port":3306},
+				"annotations":[{"timestamp":1556604172355740,"value":"ws"},{"timestamp":1556604172355780,"value":"wr"}]}]`,
+			check: func(t *testing.T, req *collectortrace.ExportTraceServiceRequest) {
+				span := onlySpan(t, req)
+				if hex.EncodeToString(span.TraceId) != "463ac35c9f6413ad48485a3953bb6124" {
+					t.Errorf("got trace ID %x", span.TraceId)
+				}
+				if len(span.ParentSpanId) != 0 {
+					t.Errorf("got parent span ID %x, want none", span.ParentSpanId)
+				}
+				if span.Kind != prototrace.Span_SPAN_KIND_CLIENT {
+					t.Errorf("got kind %v, want CLIENT", span.Kind)
+				}
+				wantAttributes(t, span.Attributes, "peer.service=mysql", "network.peer.address=::1", "network.peer.port=3306")
+				if len(span.Events) != 2 || span.Events[0].Name != "ws" || span.Events[1].TimeUnixNano != 1556604172355780000 {
+					t.Errorf("got events %v", span.Events)
+				}
+			},
+		},
+		{
+			name: "error tag",
+			body: `[{"traceId":"5af7183fb1d4cf5f","id":"352bff9a74ca9ad2","timestamp":1556604172355737,"tags":{"error":"deadlock"}}]`,
+			check: func(t *testing.T, req *collectortrace.ExportTraceServiceRequest) {
+				span := onlySpan(t, req)
+				wantStatus(t, span, prototrace.Status_STATUS_CODE_ERROR, "deadlock")
+				wantAttributes(t, span.Attributes)
+				if span.Kind != prototrace.Span_SPAN_KIND_INTERNAL {
+					t.Errorf("got kind %v, want INTERNAL", span.Kind)
+				}
+			},
+		},
+		{
+			name: "error tag without a message",
+			body: `[{"traceId":"5af7183fb1d4cf5f","id":"352bff9a74ca9ad2","timestamp":1556604172355737,"tags":{"error":"true"}}]`,
+			check: func(t *testing.T, req *collectortrace.ExportTraceServiceRequest) {
+				wantStatus(t, onlySpan(t, req), prototrace.Status_STATUS_CODE_ERROR, "")
+			},
+		},
+		{
+			name: "OpenTelemetry status and scope tags",
+			body: `[{"traceId":"5af7183fb1d4cf5f","id":"352bff9a74ca9ad2","timestamp":1556604172355737,"tags":{
+				"otel.status_code":"ERROR","otel.status_description":"timeout","otel.scope.name":"db","otel.scope.version":"1.2.0","db.system":"mysql"}}]`,
+			check: func(t *testing.T, req *collectortrace.ExportTraceServiceRequest) {
+				span := onlySpan(t, req)
+				wantStatus(t, span, prototrace.Status_STATUS_CODE_ERROR, "timeout")
+				wantAttributes(t, span.Attributes, "db.system=mysql")
+				scope := req.ResourceSpans[0].ScopeSpans[0].Scope
+				if scope.GetName() != "db" || scope.GetVersion() != "1.2.0" {
+					t.Errorf("got scope %v, want db 1.2.0", scope)
+				}
+			},
+		},
+		{
+			name: "OK status",
+			body: `[{"traceId":"5af7183fb1d4cf5f","id":"352bff9a74ca9ad2","timestamp":1556604172355737,"tags":{"otel.status_code":"OK"}}]`,
+			check: func(t *testing.T, req *collectortrace.ExportTraceServiceRequest) {
+				wantStatus(t, onlySpan(t, req), prototrace.Status_STATUS_CODE_OK, "")
+			},
+		},
+		{
+			name: "spans without a timestamp are skipped",
+			body: `[{"traceId":"5af7183fb1d4cf5f","id":"352bff9a74ca9ad2","kind":"SERVER","shared":true},
+				{"traceId":"5af7183fb1d4cf5f","id":"6b221d5bc9e6496c","name":"kept","timestamp":1556604172355737}]`,
+			check: func(t *testing.T, req *collectortrace.ExportTraceServiceRequest) {
+				if span := onlySpan(t, req); span.Name != "kept" {
+					t.Errorf("got span %q, want kept", span.Name)
+				}
+			},
+		},
+		{
+			name: "spans are grouped by local service",
+			body: `[{"traceId":"1","id":"1","timestamp":1,"localEndpoint":{"serviceName":"a"}},
+				{"traceId":"1","id":"2","timestamp":1,"localEndpoint":{"serviceName":"b"}},
+				{"traceId":"1","id":"3","timestamp":1,"localEndpoint":{"serviceName":"a"}}]`,
+			check: func(t *testing.T, req *collectortrace.ExportTraceServiceRequest) {
+				if len(req.ResourceSpans) != 2 || len(req.ResourceSpans[0].ScopeSpans[0].Spans) != 2 {
+					t.Errorf("got %d resources, want a with 2 spans and b with 1", len(req.ResourceSpans))
+				}
+			},
+		},
+		{name: "bad hex", body: `[{"traceId":"5af7183fb1d4cf5z","id":"352bff9a74ca9ad2","timestamp":1}]`, wantErr: `span 0: traceId "5af7183fb1d4cf5z" is not hex`},
+		{name: "bad parent ID", body: `[{"traceId":"5af7183fb1d4cf5f","id":"352bff9a74ca9ad2","parentId":"xyz","timestamp":1}]`, wantErr: `parentId "xyz" is not hex`},
+		{name: "missing span ID", body: `[{"traceId":"5af7183fb1d4cf5f","timestamp":1}]`, wantErr: "id must be 1 to 16 hex characters"},
+		{name: "not an array", body: `{"traceId":"5af7183fb1d4cf5f"}`, wantErr: "failed to decode Zipkin v2 JSON"},
+		{name: "not gzipped", body: backendSpan, encoding: "gzip", wantErr: "failed to decompress body"},
+		{name: "gzipped over the decompressed limit", body: strings.Repeat(" ", maxDecodedZipkinBytes+1), gzip: true, wantErr: "decompressed body exceeds 67108864 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := []byte(tt.body)
+			encoding := tt.encoding
+			if tt.gzip {
+				var buf bytes.Buffer
+				writer := gzip.NewWriter(&buf)
+				_, _ = writer.Write(body)
+				_ = writer.Close()
+				body, encoding = buf.Bytes(), "gzip"
+			}
+
+			req, err := decodeZipkin(body, encoding)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, req)
+		})
+	}
+}
+
+func TestZipkinProxyBodyLimit(t *testing.T) {
+	p := NewZipkinProxy("127.0.0.1:0", "", nil)
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = p.Stop() })
+
+	resp, err := http.Post("http://"+p.Addr()+ZipkinSpansPath, "application/json", bytes.NewReader(make([]byte, MaxZipkinBytes+1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d, want 413", resp.StatusCode)
+	}
+}
+
+func onlySpan(t *testing.T, req *collectortrace.ExportTraceServiceRequest) *prototrace.Span {
+	t.Helper()
+	var spans []*prototrace.Span
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			spans = append(spans, ss.Spans...)
+		}
+	}
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+func wantStatus(t *testing.T, span *prototrace.Span, code prototrace.Status_StatusCode, message string) {
+	t.Helper()
+	if span.Status.GetCode() != code || span.Status.GetMessage() != message {
+		t.Errorf("got status %v %q, want %v %q", span.Status.GetCode(), span.Status.GetMessage(), code, message)
+	}
+}
+
+// wantAttributes compares attributes, in order, as key=value.
+func wantAttributes(t *testing.T, attrs []*commonpb.KeyValue, want ...string) {
+	t.Helper()
+	got := make([]string, 0, len(attrs))
+	for _, kv := range attrs {
+		value := kv.Value.GetStringValue()
+		if v, ok := kv.Value.GetValue().(*commonpb.AnyValue_IntValue); ok {
+			value = strconv.FormatInt(v.IntValue, 10)
+		}
+		got = append(got, kv.Key+"="+value)
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("got attributes [%s], want [%s]", strings.Join(got, ", "), strings.Join(want, ", "))
+	}
+}
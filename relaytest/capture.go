@@ -0,0 +1,82 @@
+package relaytest
+
+import (
+	"context"
+	"sync"
+
+	relay "github.com/jimschubert/otel-relay/inspector"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	_ relay.Emitter = (*capture)(nil)
+)
+
+// capture is an in-memory emitter which wakes waiters whenever a request arrives.
+type capture struct {
+	mu      sync.Mutex
+	traces  []*collectortrace.ExportTraceServiceRequest
+	metrics []*collectormetrics.ExportMetricsServiceRequest
+	logs    []*collectorlogs.ExportLogsServiceRequest
+	notify  chan struct{}
+}
+
+func newCapture() *capture {
+	return &capture{notify: make(chan struct{})}
+}
+
+func (c *capture) EmitTrace(_ context.Context, req *collectortrace.ExportTraceServiceRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.traces = append(c.traces, proto.Clone(req).(*collectortrace.ExportTraceServiceRequest))
+	c.broadcast()
+	return nil
+}
+
+func (c *capture) EmitMetric(_ context.Context, req *collectormetrics.ExportMetricsServiceRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = append(c.metrics, proto.Clone(req).(*collectormetrics.ExportMetricsServiceRequest))
+	c.broadcast()
+	return nil
+}
+
+func (c *capture) EmitLog(_ context.Context, req *collectorlogs.ExportLogsServiceRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logs = append(c.logs, proto.Clone(req).(*collectorlogs.ExportLogsServiceRequest))
+	c.broadcast()
+	return nil
+}
+
+// broadcast must be called with mu held.
+func (c *capture) broadcast() {
+	close(c.notify)
+	c.notify = make(chan struct{})
+}
+
+// changed returns a channel closed on the next captured request.
+func (c *capture) changed() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.notify
+}
+
+func (c *capture) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.traces = nil
+	c.metrics = nil
+	c.logs = nil
+}
+
+func (c *capture) snapshot() ([]*collectortrace.ExportTraceServiceRequest, []*collectormetrics.ExportMetricsServiceRequest, []*collectorlogs.ExportLogsServiceRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*collectortrace.ExportTraceServiceRequest(nil), c.traces...),
+		append([]*collectormetrics.ExportMetricsServiceRequest(nil), c.metrics...),
+		append([]*collectorlogs.ExportLogsServiceRequest(nil), c.logs...)
+}
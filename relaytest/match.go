@@ -0,0 +1,106 @@
+package relaytest
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+func hasAttributes(want []attribute.KeyValue, attrs []*commonpb.KeyValue, res *resourcepb.Resource) bool {
+	for _, kv := range want {
+		value := lookup(attrs, string(kv.Key))
+		if value == nil && res != nil {
+			value = lookup(res.Attributes, string(kv.Key))
+		}
+		if value == nil || !valueEquals(kv.Value, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func lookup(attrs []*commonpb.KeyValue, key string) *commonpb.AnyValue {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+func valueEquals(want attribute.Value, got *commonpb.AnyValue) bool {
+	switch want.Type() {
+	case attribute.BOOL:
+		v, ok := got.Value.(*commonpb.AnyValue_BoolValue)
+		return ok && v.BoolValue == want.AsBool()
+	case attribute.INT64:
+		v, ok := got.Value.(*commonpb.AnyValue_IntValue)
+		return ok && v.IntValue == want.AsInt64()
+	case attribute.FLOAT64:
+		v, ok := got.Value.(*commonpb.AnyValue_DoubleValue)
+		return ok && v.DoubleValue == want.AsFloat64()
+	case attribute.STRING:
+		v, ok := got.Value.(*commonpb.AnyValue_StringValue)
+		return ok && v.StringValue == want.AsString()
+	case attribute.BOOLSLICE:
+		return sliceEquals(want.AsBoolSlice(), got, attribute.BoolValue)
+	case attribute.INT64SLICE:
+		return sliceEquals(want.AsInt64Slice(), got, attribute.Int64Value)
+	case attribute.FLOAT64SLICE:
+		return sliceEquals(want.AsFloat64Slice(), got, attribute.Float64Value)
+	case attribute.STRINGSLICE:
+		return sliceEquals(want.AsStringSlice(), got, attribute.StringValue)
+	default:
+		return false
+	}
+}
+
+func sliceEquals[T any](want []T, got *commonpb.AnyValue, toValue func(T) attribute.Value) bool {
+	v, ok := got.Value.(*commonpb.AnyValue_ArrayValue)
+	if !ok || len(v.ArrayValue.Values) != len(want) {
+		return false
+	}
+	for idx, w := range want {
+		if !valueEquals(toValue(w), v.ArrayValue.Values[idx]) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyValueString renders a value as plain text for body matching and failure messages.
+func anyValueString(value *commonpb.AnyValue) string {
+	if value == nil {
+		return ""
+	}
+
+	switch v := value.Value.(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return fmt.Sprintf("%t", v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return fmt.Sprintf("%d", v.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return fmt.Sprint(v.DoubleValue)
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]string, len(v.ArrayValue.Values))
+		for idx, val := range v.ArrayValue.Values {
+			values[idx] = anyValueString(val)
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case *commonpb.AnyValue_KvlistValue:
+		values := make([]string, len(v.KvlistValue.Values))
+		for idx, kv := range v.KvlistValue.Values {
+			values[idx] = kv.Key + "=" + anyValueString(kv.Value)
+		}
+		return "{" + strings.Join(values, ", ") + "}"
+	case *commonpb.AnyValue_BytesValue:
+		return fmt.Sprintf("%x", v.BytesValue)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,255 @@
+package relaytest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// Span is a captured span along with the resource and scope it was exported under.
+type Span struct {
+	*prototrace.Span
+	Resource *resourcepb.Resource
+	Scope    *commonpb.InstrumentationScope
+}
+
+// Duration is the span's end time minus its start time.
+func (s Span) Duration() time.Duration {
+	return time.Duration(int64(s.EndTimeUnixNano) - int64(s.StartTimeUnixNano))
+}
+
+// DataPoint is a single captured metric data point, flattened from its metric.
+type DataPoint struct {
+	Metric     *protometrics.Metric
+	Resource   *resourcepb.Resource
+	Scope      *commonpb.InstrumentationScope
+	Attributes []*commonpb.KeyValue
+	StartTime  time.Time
+	Time       time.Time
+
+	// Value is the gauge or sum value as a float64, or the sum of observations for histograms and summaries.
+	Value float64
+	// Count is the number of observations for histograms and summaries; zero for gauges and sums.
+	Count uint64
+}
+
+// Log is a captured log record along with the resource and scope it was exported under.
+type Log struct {
+	*protologs.LogRecord
+	Resource *resourcepb.Resource
+	Scope    *commonpb.InstrumentationScope
+}
+
+// Spans returns every span captured so far, in arrival order.
+func (r *Relay) Spans() []Span {
+	traces, _, _ := r.capture.snapshot()
+	spans := make([]Span, 0)
+	for _, req := range traces {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					spans = append(spans, Span{Span: span, Resource: rs.Resource, Scope: ss.Scope})
+				}
+			}
+		}
+	}
+	return spans
+}
+
+// DataPoints returns every metric data point captured so far, in arrival order.
+func (r *Relay) DataPoints() []DataPoint {
+	_, metrics, _ := r.capture.snapshot()
+	points := make([]DataPoint, 0)
+	for _, req := range metrics {
+		for _, rm := range req.ResourceMetrics {
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					points = append(points, dataPoints(m, rm.Resource, sm.Scope)...)
+				}
+			}
+		}
+	}
+	return points
+}
+
+// Logs returns every log record captured so far, in arrival order.
+func (r *Relay) Logs() []Log {
+	_, _, logs := r.capture.snapshot()
+	records := make([]Log, 0)
+	for _, req := range logs {
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				for _, record := range sl.LogRecords {
+					records = append(records, Log{LogRecord: record, Resource: rl.Resource, Scope: sl.Scope})
+				}
+			}
+		}
+	}
+	return records
+}
+
+// FindSpans returns captured spans with the given name and all the given attributes.
+// Attributes match against the span first, then its resource.
+func (r *Relay) FindSpans(name string, attrs ...attribute.KeyValue) []Span {
+	return slices.DeleteFunc(r.Spans(), func(s Span) bool {
+		return s.Name != name || !hasAttributes(attrs, s.Attributes, s.Resource)
+	})
+}
+
+// FindDataPoints returns captured data points of the named metric with all the given attributes.
+// Attributes match against the data point first, then its resource.
+func (r *Relay) FindDataPoints(metric string, attrs ...attribute.KeyValue) []DataPoint {
+	return slices.DeleteFunc(r.DataPoints(), func(dp DataPoint) bool {
+		return dp.Metric.Name != metric || !hasAttributes(attrs, dp.Attributes, dp.Resource)
+	})
+}
+
+// FindLogs returns captured log records at or above severity whose body contains body and which have all the given attributes.
+// A zero severity or empty body matches any record. Attributes match against the record first, then its resource.
+func (r *Relay) FindLogs(severity protologs.SeverityNumber, body string, attrs ...attribute.KeyValue) []Log {
+	return slices.DeleteFunc(r.Logs(), func(l Log) bool {
+		if l.SeverityNumber < severity {
+			return true
+		}
+		if body != "" && !strings.Contains(anyValueString(l.Body), body) {
+			return true
+		}
+		return !hasAttributes(attrs, l.Attributes, l.Resource)
+	})
+}
+
+// WaitForSpan waits for a span matching FindSpans and returns the first, failing the test on timeout.
+func (r *Relay) WaitForSpan(t testing.TB, name string, attrs ...attribute.KeyValue) Span {
+	t.Helper()
+	found, ok := wait(r, func() []Span { return r.FindSpans(name, attrs...) })
+	if !ok {
+		seen := make([]string, 0)
+		for _, s := range r.Spans() {
+			seen = append(seen, s.Name)
+		}
+		t.Fatalf("relaytest: timed out after %s waiting for span %q%s; captured spans: %v", r.timeout, name, describe(attrs), seen)
+		return Span{}
+	}
+	return found[0]
+}
+
+// WaitForDataPoint waits for a data point matching FindDataPoints and returns the most recent, failing the test on timeout.
+func (r *Relay) WaitForDataPoint(t testing.TB, metric string, attrs ...attribute.KeyValue) DataPoint {
+	t.Helper()
+	found, ok := wait(r, func() []DataPoint { return r.FindDataPoints(metric, attrs...) })
+	if !ok {
+		seen := make([]string, 0)
+		for _, dp := range r.DataPoints() {
+			if !slices.Contains(seen, dp.Metric.Name) {
+				seen = append(seen, dp.Metric.Name)
+			}
+		}
+		t.Fatalf("relaytest: timed out after %s waiting for metric %q%s; captured metrics: %v", r.timeout, metric, describe(attrs), seen)
+		return DataPoint{}
+	}
+	return found[len(found)-1]
+}
+
+// WaitForLog waits for a log record matching FindLogs and returns the first, failing the test on timeout.
+func (r *Relay) WaitForLog(t testing.TB, severity protologs.SeverityNumber, body string, attrs ...attribute.KeyValue) Log {
+	t.Helper()
+	found, ok := wait(r, func() []Log { return r.FindLogs(severity, body, attrs...) })
+	if !ok {
+		t.Fatalf("relaytest: timed out after %s waiting for log (severity >= %s, body containing %q)%s; captured %d log records",
+			r.timeout, severity, body, describe(attrs), len(r.Logs()))
+		return Log{}
+	}
+	return found[0]
+}
+
+// WaitForMetricValue waits until the most recent data point of the named metric with the given attributes has the wanted value.
+func (r *Relay) WaitForMetricValue(t testing.TB, metric string, want float64, attrs ...attribute.KeyValue) DataPoint {
+	t.Helper()
+	found, ok := wait(r, func() []DataPoint {
+		points := r.FindDataPoints(metric, attrs...)
+		if len(points) == 0 || points[len(points)-1].Value != want {
+			return nil
+		}
+		return points[len(points)-1:]
+	})
+	if !ok {
+		got := "no data points"
+		if points := r.FindDataPoints(metric, attrs...); len(points) > 0 {
+			got = fmt.Sprintf("last value %v", points[len(points)-1].Value)
+		}
+		t.Fatalf("relaytest: timed out after %s waiting for metric %q%s to equal %v; %s", r.timeout, metric, describe(attrs), want, got)
+		return DataPoint{}
+	}
+	return found[0]
+}
+
+func dataPoints(m *protometrics.Metric, res *resourcepb.Resource, scope *commonpb.InstrumentationScope) []DataPoint {
+	points := make([]DataPoint, 0)
+	add := func(attrs []*commonpb.KeyValue, start, ts uint64, value float64, count uint64) {
+		points = append(points, DataPoint{
+			Metric:     m,
+			Resource:   res,
+			Scope:      scope,
+			Attributes: attrs,
+			StartTime:  time.Unix(0, int64(start)),
+			Time:       time.Unix(0, int64(ts)),
+			Value:      value,
+			Count:      count,
+		})
+	}
+
+	switch data := m.Data.(type) {
+	case *protometrics.Metric_Gauge:
+		for _, dp := range data.Gauge.DataPoints {
+			add(dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano, numberValue(dp), 0)
+		}
+	case *protometrics.Metric_Sum:
+		for _, dp := range data.Sum.DataPoints {
+			add(dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano, numberValue(dp), 0)
+		}
+	case *protometrics.Metric_Histogram:
+		for _, dp := range data.Histogram.DataPoints {
+			add(dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano, dp.GetSum(), dp.Count)
+		}
+	case *protometrics.Metric_ExponentialHistogram:
+		for _, dp := range data.ExponentialHistogram.DataPoints {
+			add(dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano, dp.GetSum(), dp.Count)
+		}
+	case *protometrics.Metric_Summary:
+		for _, dp := range data.Summary.DataPoints {
+			add(dp.Attributes, dp.StartTimeUnixNano, dp.TimeUnixNano, dp.Sum, dp.Count)
+		}
+	}
+	return points
+}
+
+func numberValue(dp *protometrics.NumberDataPoint) float64 {
+	switch v := dp.Value.(type) {
+	case *protometrics.NumberDataPoint_AsInt:
+		return float64(v.AsInt)
+	case *protometrics.NumberDataPoint_AsDouble:
+		return v.AsDouble
+	default:
+		return 0
+	}
+}
+
+func describe(attrs []attribute.KeyValue) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	parts := make([]string, len(attrs))
+	for idx, kv := range attrs {
+		parts[idx] = fmt.Sprintf("%s=%s", kv.Key, kv.Value.Emit())
+	}
+	return " with " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,128 @@
+// Package relaytest runs an in-process otel-relay for tests and offers helpers to assert on the telemetry it captured.
+//
+//	r := relaytest.Start(t)
+//	// point the SDK exporter under test at r.GRPCEndpoint() or r.HTTPEndpoint()
+//	span := r.WaitForSpan(t, "database-query", attribute.String("db.system", "postgresql"))
+package relaytest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	relay "github.com/jimschubert/otel-relay/inspector"
+	"github.com/jimschubert/otel-relay/proxy"
+)
+
+const defaultTimeout = 5 * time.Second
+
+type options struct {
+	timeout      time.Duration
+	upstream     string
+	upstreamHttp string
+}
+
+type Option func(*options)
+
+// WithTimeout sets how long the WaitFor* helpers wait before failing the test. Defaults to 5s.
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.timeout = timeout
+	}
+}
+
+// WithUpstream forwards everything received over gRPC to the given OTLP/gRPC collector address.
+func WithUpstream(addr string) Option {
+	return func(opts *options) {
+		opts.upstream = addr
+	}
+}
+
+// WithUpstreamHttp forwards everything received over HTTP to the given OTLP/HTTP collector URL.
+func WithUpstreamHttp(url string) Option {
+	return func(opts *options) {
+		opts.upstreamHttp = url
+	}
+}
+
+// Relay is an in-process relay listening for OTLP over gRPC and HTTP on ephemeral loopback ports.
+type Relay struct {
+	capture   *capture
+	timeout   time.Duration
+	grpcProxy *proxy.OTLPProxy
+	httpProxy *proxy.HTTPProxy
+}
+
+// Start launches a Relay and registers its shutdown with t.Cleanup.
+func Start(t testing.TB, opts ...Option) *Relay {
+	t.Helper()
+
+	o := &options{timeout: defaultTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	c := newCapture()
+	insp := relay.NewInspector(relay.WithEmitter(c))
+
+	r := &Relay{
+		capture:   c,
+		timeout:   o.timeout,
+		grpcProxy: proxy.NewOTLPProxy("127.0.0.1:0", o.upstream, insp),
+		httpProxy: proxy.NewHTTPProxy("127.0.0.1:0", o.upstreamHttp, insp),
+	}
+
+	if err := r.grpcProxy.Start(); err != nil {
+		t.Fatalf("relaytest: failed to start gRPC proxy: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = r.grpcProxy.Stop()
+	})
+
+	if err := r.httpProxy.Start(); err != nil {
+		t.Fatalf("relaytest: failed to start HTTP proxy: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = r.httpProxy.Stop()
+	})
+
+	return r
+}
+
+// GRPCEndpoint returns the host:port to configure as an OTLP/gRPC exporter endpoint (insecure).
+func (r *Relay) GRPCEndpoint() string {
+	return r.grpcProxy.Addr()
+}
+
+// HTTPEndpoint returns the base URL to configure as an OTLP/HTTP exporter endpoint, e.g. http://127.0.0.1:43210.
+func (r *Relay) HTTPEndpoint() string {
+	addr := r.httpProxy.Addr()
+	if strings.HasPrefix(addr, ":") {
+		addr = "127.0.0.1" + addr
+	}
+	return "http://" + addr
+}
+
+// Reset discards everything captured so far.
+func (r *Relay) Reset() {
+	r.capture.reset()
+}
+
+// wait polls find until it returns at least one item or the relay's timeout elapses.
+func wait[T any](r *Relay, find func() []T) ([]T, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	for {
+		changed := r.capture.changed()
+		if found := find(); len(found) > 0 {
+			return found, true
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
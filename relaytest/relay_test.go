@@ -0,0 +1,167 @@
+package relaytest_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jimschubert/otel-relay/relaytest"
+	"go.opentelemetry.io/otel/attribute"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+var resource = &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttribute("service.name", "checkout")}}
+
+func TestWaitForSpanOverGRPC(t *testing.T) {
+	r := relaytest.Start(t)
+
+	start := uint64(time.Now().UnixNano())
+	_, err := collectortrace.NewTraceServiceClient(dial(t, r)).Export(context.Background(), &collectortrace.ExportTraceServiceRequest{
+		ResourceSpans: []*prototrace.ResourceSpans{{
+			Resource: resource,
+			ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{
+				{
+					TraceId:           bytes.Repeat([]byte{1}, 16),
+					SpanId:            bytes.Repeat([]byte{2}, 8),
+					Name:              "database-query",
+					StartTimeUnixNano: start,
+					EndTimeUnixNano:   start + uint64(40*time.Millisecond),
+					Attributes:        []*commonpb.KeyValue{stringAttribute("db.system", "postgresql")},
+				},
+			}}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	span := r.WaitForSpan(t, "database-query", attribute.String("db.system", "postgresql"), attribute.String("service.name", "checkout"))
+	if span.Duration() != 40*time.Millisecond {
+		t.Errorf("got duration %s, want 40ms", span.Duration())
+	}
+	if got := r.FindSpans("database-query", attribute.String("db.system", "mysql")); len(got) != 0 {
+		t.Errorf("got %d spans for a mismatched attribute, want 0", len(got))
+	}
+}
+
+func TestWaitForMetricValueOverHTTP(t *testing.T) {
+	r := relaytest.Start(t)
+
+	for _, value := range []int64{1, 3} {
+		body, err := proto.Marshal(&collectormetrics.ExportMetricsServiceRequest{
+			ResourceMetrics: []*protometrics.ResourceMetrics{{
+				Resource: resource,
+				ScopeMetrics: []*protometrics.ScopeMetrics{{Metrics: []*protometrics.Metric{{
+					Name: "orders",
+					Data: &protometrics.Metric_Sum{Sum: &protometrics.Sum{DataPoints: []*protometrics.NumberDataPoint{{
+						Attributes: []*commonpb.KeyValue{stringAttribute("status", "paid")},
+						Value:      &protometrics.NumberDataPoint_AsInt{AsInt: value},
+					}}}},
+				}}}},
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.Post(r.HTTPEndpoint()+"/v1/metrics", "application/x-protobuf", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("export failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got status %d, want 200", resp.StatusCode)
+		}
+	}
+
+	dp := r.WaitForMetricValue(t, "orders", 3, attribute.String("status", "paid"))
+	if dp.Metric.GetSum() == nil {
+		t.Errorf("got %T, want a sum", dp.Metric.Data)
+	}
+	if got := len(r.FindDataPoints("orders")); got != 2 {
+		t.Errorf("got %d data points, want 2", got)
+	}
+}
+
+func TestWaitForLogAndReset(t *testing.T) {
+	r := relaytest.Start(t)
+
+	_, err := collectorlogs.NewLogsServiceClient(dial(t, r)).Export(context.Background(), &collectorlogs.ExportLogsServiceRequest{
+		ResourceLogs: []*protologs.ResourceLogs{{
+			Resource: resource,
+			ScopeLogs: []*protologs.ScopeLogs{{LogRecords: []*protologs.LogRecord{
+				{SeverityNumber: protologs.SeverityNumber_SEVERITY_NUMBER_INFO, Body: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "order placed"}}},
+				{SeverityNumber: protologs.SeverityNumber_SEVERITY_NUMBER_ERROR, Body: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "payment declined"}}},
+			}}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	log := r.WaitForLog(t, protologs.SeverityNumber_SEVERITY_NUMBER_WARN, "", attribute.String("service.name", "checkout"))
+	if got := log.Body.GetStringValue(); got != "payment declined" {
+		t.Errorf("got log %q, want the error", got)
+	}
+	if got := len(r.FindLogs(0, "order")); got != 1 {
+		t.Errorf("got %d logs containing order, want 1", got)
+	}
+
+	r.Reset()
+	if got := len(r.Logs()); got != 0 {
+		t.Errorf("got %d logs after Reset, want 0", got)
+	}
+}
+
+func TestWaitForSpanTimeout(t *testing.T) {
+	r := relaytest.Start(t, relaytest.WithTimeout(50*time.Millisecond))
+
+	tb := &fatalRecorder{TB: t}
+	r.WaitForSpan(tb, "never-sent")
+	if !strings.Contains(tb.message, `timed out after 50ms waiting for span "never-sent"`) {
+		t.Errorf("got failure %q, want a timeout", tb.message)
+	}
+}
+
+// fatalRecorder records Fatal and Fatalf instead of stopping the test, to check the helpers' failure messages.
+type fatalRecorder struct {
+	testing.TB
+	message string
+}
+
+func (f *fatalRecorder) Helper() {}
+
+func (f *fatalRecorder) Fatal(args ...any) {
+	f.message = fmt.Sprint(args...)
+}
+
+func (f *fatalRecorder) Fatalf(format string, args ...any) {
+	f.message = fmt.Sprintf(format, args...)
+}
+
+func dial(t *testing.T, r *relaytest.Relay) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.NewClient(r.GRPCEndpoint(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial relay: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
@@ -0,0 +1,42 @@
+package relaytest
+
+import (
+	"flag"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/jimschubert/otel-relay/snapshot"
+)
+
+// UpdateEnv is the environment variable which, when set to a true value, makes MatchSnapshot rewrite golden files.
+const UpdateEnv = "RELAYTEST_UPDATE"
+
+// Snapshot returns everything captured so far, ready for snapshot.Compare or snapshot.Marshal.
+func (r *Relay) Snapshot() *snapshot.Snapshot {
+	traces, metrics, logs := r.capture.snapshot()
+	return &snapshot.Snapshot{Traces: traces, Metrics: metrics, Logs: logs}
+}
+
+// MatchSnapshot compares everything captured so far against the golden file at path, failing the test with a diff on mismatch.
+//
+// Golden files are rewritten when the test binary defines an -update flag and it is set (go test -update),
+// or when RELAYTEST_UPDATE is true.
+func (r *Relay) MatchSnapshot(t testing.TB, path string, opts ...snapshot.Option) {
+	t.Helper()
+	if err := snapshot.Compare(path, r.Snapshot(), updating(), opts...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func updating() bool {
+	if f := flag.Lookup("update"); f != nil {
+		if getter, ok := f.Value.(flag.Getter); ok {
+			if update, ok := getter.Get().(bool); ok && update {
+				return true
+			}
+		}
+	}
+	update, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return update
+}
@@ -0,0 +1,73 @@
+package relaytest_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jimschubert/otel-relay/relaytest"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// update is the -update flag MatchSnapshot looks for, as a test using it would define.
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestMatchSnapshot(t *testing.T) {
+	r := relaytest.Start(t)
+	client := collectortrace.NewTraceServiceClient(dial(t, r))
+	export := func(name string) {
+		t.Helper()
+		_, err := client.Export(context.Background(), &collectortrace.ExportTraceServiceRequest{
+			ResourceSpans: []*prototrace.ResourceSpans{{
+				Resource:   resource,
+				ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{{Name: name}}}},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("export failed: %v", err)
+		}
+		r.WaitForSpan(t, name)
+	}
+	export("checkout")
+
+	tests := []struct {
+		name   string
+		path   string
+		update func(t *testing.T)
+	}{
+		{name: "RELAYTEST_UPDATE", path: "env.json", update: func(t *testing.T) { t.Setenv(relaytest.UpdateEnv, "true") }},
+		{name: "-update flag", path: "flag.json", update: func(t *testing.T) {
+			*update = true
+			t.Cleanup(func() { *update = false })
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.path)
+			t.Run("update", func(t *testing.T) {
+				tt.update(t)
+				r.MatchSnapshot(t, path)
+			})
+			if _, err := os.Stat(path); err != nil {
+				t.Fatalf("golden file was not written: %v", err)
+			}
+			r.MatchSnapshot(t, path)
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "golden.json")
+	t.Setenv(relaytest.UpdateEnv, "true")
+	r.MatchSnapshot(t, path)
+	t.Setenv(relaytest.UpdateEnv, "false")
+
+	export("refund")
+	tb := &fatalRecorder{TB: t}
+	r.MatchSnapshot(tb, path)
+	if !strings.Contains(tb.message, "does not match golden file") || !strings.Contains(tb.message, `"name": "refund"`) {
+		t.Errorf("got failure %q, want a diff adding the span", tb.message)
+	}
+}
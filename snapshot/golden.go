@@ -0,0 +1,148 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type document struct {
+	Traces  json.RawMessage `json:"traces,omitempty"`
+	Metrics json.RawMessage `json:"metrics,omitempty"`
+	Logs    json.RawMessage `json:"logs,omitempty"`
+}
+
+// Marshal normalizes s and renders it as indented OTLP/JSON, stable across runs and builds.
+func Marshal(s *Snapshot, opts ...Option) ([]byte, error) {
+	normalized := Normalize(s, opts...)
+
+	var doc document
+	var err error
+	if len(normalized.Traces) > 0 {
+		if doc.Traces, err = protojson.Marshal(normalized.Traces[0]); err != nil {
+			return nil, fmt.Errorf("failed to marshal traces: %w", err)
+		}
+	}
+	if len(normalized.Metrics) > 0 {
+		if doc.Metrics, err = protojson.Marshal(normalized.Metrics[0]); err != nil {
+			return nil, fmt.Errorf("failed to marshal metrics: %w", err)
+		}
+	}
+	if len(normalized.Logs) > 0 {
+		if doc.Logs, err = protojson.Marshal(normalized.Logs[0]); err != nil {
+			return nil, fmt.Errorf("failed to marshal logs: %w", err)
+		}
+	}
+
+	// encoding/json re-indents the embedded protojson output, which is deliberately unstable in its whitespace.
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// Compare checks s against the golden file at path. With update, the golden file is (re)written instead.
+// A mismatch is reported as an error containing a line diff.
+func Compare(path string, s *Snapshot, update bool, opts ...Option) error {
+	got, err := Marshal(s, opts...)
+	if err != nil {
+		return err
+	}
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create golden file directory: %w", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			return fmt.Errorf("failed to write golden file: %w", err)
+		}
+		return nil
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("golden file %s does not exist, run with update enabled to create it", path)
+		}
+		return fmt.Errorf("failed to read golden file: %w", err)
+	}
+
+	if bytes.Equal(want, got) {
+		return nil
+	}
+	return fmt.Errorf("telemetry does not match golden file %s (-want +got):\n%s", path, diff(string(want), string(got)))
+}
+
+// diff renders a line diff of want and got with a few lines of context around each change.
+func diff(want, got string) string {
+	const context = 3
+
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		default:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		}
+	}
+
+	show := make([]bool, len(lines))
+	for idx, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for k := max(0, idx-context); k <= min(len(lines)-1, idx+context); k++ {
+			show[k] = true
+		}
+	}
+
+	var buf strings.Builder
+	for idx, l := range lines {
+		if !show[idx] {
+			continue
+		}
+		if idx > 0 && !show[idx-1] && buf.Len() > 0 {
+			buf.WriteString("  ...\n")
+		}
+		fmt.Fprintf(&buf, "%c %s\n", l.op, l.text)
+	}
+	return buf.String()
+}
+
+type diffLine struct {
+	op   byte
+	text string
+}
@@ -0,0 +1,108 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		got  string
+		diff string
+	}{
+		{
+			name: "changed line",
+			want: "a\nb\nc",
+			got:  "a\nx\nc",
+			diff: "  a\n+ x\n- b\n  c\n",
+		},
+		{
+			name: "added line",
+			want: "a\nc",
+			got:  "a\nb\nc",
+			diff: "  a\n+ b\n  c\n",
+		},
+		{
+			name: "removed line",
+			want: "a\nb\nc",
+			got:  "a\nc",
+			diff: "  a\n- b\n  c\n",
+		},
+		{
+			name: "distant changes are separated",
+			want: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11",
+			got:  "x\n2\n3\n4\n5\n6\n7\n8\n9\n10\ny",
+			diff: "+ x\n- 1\n  2\n  3\n  4\n  ...\n  8\n  9\n  10\n+ y\n- 11\n",
+		},
+		{
+			name: "context is limited to three lines",
+			want: "1\n2\n3\n4\n5\n6\n7\n8\n9",
+			got:  "1\n2\n3\n4\nx\n6\n7\n8\n9",
+			diff: "  2\n  3\n  4\n+ x\n- 5\n  6\n  7\n  8\n",
+		},
+		{
+			name: "identical",
+			want: "a\nb",
+			got:  "a\nb",
+			diff: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diff(tt.want, tt.got); got != tt.diff {
+				t.Errorf("got diff:\n%s\nwant:\n%s", got, tt.diff)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	spans := func(names ...string) *Snapshot {
+		ss := &prototrace.ScopeSpans{}
+		for _, name := range names {
+			ss.Spans = append(ss.Spans, &prototrace.Span{Name: name})
+		}
+		return &Snapshot{Traces: []*collectortrace.ExportTraceServiceRequest{{
+			ResourceSpans: []*prototrace.ResourceSpans{{ScopeSpans: []*prototrace.ScopeSpans{ss}}},
+		}}}
+	}
+	path := filepath.Join(t.TempDir(), "testdata", "golden.json")
+
+	if err := Compare(path, spans("checkout"), false); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("got error %v for a missing golden file, want it to mention the file doesn't exist", err)
+	}
+
+	if err := Compare(path, spans("checkout"), true); err != nil {
+		t.Fatalf("unexpected error updating: %v", err)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden file was not written: %v", err)
+	}
+	if !strings.Contains(string(written), `"name": "checkout"`) {
+		t.Errorf("expected the span in the golden file, got:\n%s", written)
+	}
+
+	if err := Compare(path, spans("checkout"), false); err != nil {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+
+	err = Compare(path, spans("checkout", "refund"), false)
+	if err == nil {
+		t.Fatal("expected a mismatch for an extra span")
+	}
+	added := false
+	for _, line := range strings.Split(err.Error(), "\n") {
+		added = added || (strings.HasPrefix(line, "+") && strings.Contains(line, `"name": "refund"`))
+	}
+	if !strings.Contains(err.Error(), "(-want +got)") || !added {
+		t.Errorf("expected a diff adding the span, got:\n%v", err)
+	}
+}
@@ -0,0 +1,423 @@
+package snapshot
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protologs "go.opentelemetry.io/proto/otlp/logs/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultIgnoredResourceAttributes are resource attributes which differ between hosts and runs.
+// Entries ending in "." match every key with that prefix.
+var DefaultIgnoredResourceAttributes = []string{
+	"host.",
+	"os.",
+	"process.",
+	"container.",
+	"k8s.",
+	"service.instance.id",
+	"telemetry.sdk.version",
+	"telemetry.distro.version",
+}
+
+type Options struct {
+	ignoredResourceAttributes []string
+	keepMetricValues          bool
+}
+
+type Option func(*Options)
+
+// WithIgnoredResourceAttributes removes additional resource attributes; entries ending in "." are prefixes.
+func WithIgnoredResourceAttributes(keys ...string) Option {
+	return func(opts *Options) {
+		opts.ignoredResourceAttributes = append(opts.ignoredResourceAttributes, keys...)
+	}
+}
+
+// WithMetricValues keeps data point values, counts and buckets, which are otherwise stripped
+// so that only metric definitions and attribute sets are compared.
+func WithMetricValues() Option {
+	return func(opts *Options) {
+		opts.keepMetricValues = true
+	}
+}
+
+// Normalize merges all requests of each signal into one, removes everything that differs between runs
+// (timestamps, trace and span IDs, host-specific resource attributes, exemplars and, by default, metric values),
+// and sorts resources, scopes and items so the result is independent of batching and arrival order.
+func Normalize(s *Snapshot, opts ...Option) *Snapshot {
+	options := &Options{
+		ignoredResourceAttributes: slices.Clone(DefaultIgnoredResourceAttributes),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	normalized := &Snapshot{}
+	if traces := options.normalizeTraces(s.Traces); len(traces.ResourceSpans) > 0 {
+		normalized.Traces = []*collectortrace.ExportTraceServiceRequest{traces}
+	}
+	if metrics := options.normalizeMetrics(s.Metrics); len(metrics.ResourceMetrics) > 0 {
+		normalized.Metrics = []*collectormetrics.ExportMetricsServiceRequest{metrics}
+	}
+	if logs := options.normalizeLogs(s.Logs); len(logs.ResourceLogs) > 0 {
+		normalized.Logs = []*collectorlogs.ExportLogsServiceRequest{logs}
+	}
+	return normalized
+}
+
+func (o *Options) normalizeTraces(reqs []*collectortrace.ExportTraceServiceRequest) *collectortrace.ExportTraceServiceRequest {
+	resources := newGroup[*prototrace.ResourceSpans]()
+	scopes := make(map[*prototrace.ResourceSpans]*group[*prototrace.ScopeSpans])
+
+	for _, req := range reqs {
+		for _, rs := range req.ResourceSpans {
+			res := o.normalizeResource(rs.Resource)
+			target := resources.get(key(res)+rs.SchemaUrl, func() *prototrace.ResourceSpans {
+				return &prototrace.ResourceSpans{Resource: res, SchemaUrl: rs.SchemaUrl}
+			})
+			if scopes[target] == nil {
+				scopes[target] = newGroup[*prototrace.ScopeSpans]()
+			}
+
+			for _, ss := range rs.ScopeSpans {
+				scope := normalizeScope(ss.Scope)
+				scopeTarget := scopes[target].get(key(scope)+ss.SchemaUrl, func() *prototrace.ScopeSpans {
+					return &prototrace.ScopeSpans{Scope: scope, SchemaUrl: ss.SchemaUrl}
+				})
+				for _, span := range ss.Spans {
+					scopeTarget.Spans = append(scopeTarget.Spans, normalizeSpan(span))
+				}
+			}
+		}
+	}
+
+	out := &collectortrace.ExportTraceServiceRequest{}
+	for _, rs := range resources.sorted() {
+		rs.ScopeSpans = scopes[rs].sorted()
+		for _, ss := range rs.ScopeSpans {
+			sortByKey(ss.Spans)
+		}
+		out.ResourceSpans = append(out.ResourceSpans, rs)
+	}
+	return out
+}
+
+func (o *Options) normalizeMetrics(reqs []*collectormetrics.ExportMetricsServiceRequest) *collectormetrics.ExportMetricsServiceRequest {
+	resources := newGroup[*protometrics.ResourceMetrics]()
+	scopes := make(map[*protometrics.ResourceMetrics]*group[*protometrics.ScopeMetrics])
+	metrics := make(map[*protometrics.ScopeMetrics]*group[*protometrics.Metric])
+
+	for _, req := range reqs {
+		for _, rm := range req.ResourceMetrics {
+			res := o.normalizeResource(rm.Resource)
+			target := resources.get(key(res)+rm.SchemaUrl, func() *protometrics.ResourceMetrics {
+				return &protometrics.ResourceMetrics{Resource: res, SchemaUrl: rm.SchemaUrl}
+			})
+			if scopes[target] == nil {
+				scopes[target] = newGroup[*protometrics.ScopeMetrics]()
+			}
+
+			for _, sm := range rm.ScopeMetrics {
+				scope := normalizeScope(sm.Scope)
+				scopeTarget := scopes[target].get(key(scope)+sm.SchemaUrl, func() *protometrics.ScopeMetrics {
+					return &protometrics.ScopeMetrics{Scope: scope, SchemaUrl: sm.SchemaUrl}
+				})
+				if metrics[scopeTarget] == nil {
+					metrics[scopeTarget] = newGroup[*protometrics.Metric]()
+				}
+
+				for _, m := range sm.Metrics {
+					definition := metricDefinition(m)
+					metricTarget := metrics[scopeTarget].get(key(definition), func() *protometrics.Metric {
+						return definition
+					})
+					o.mergeDataPoints(metricTarget, m)
+				}
+			}
+		}
+	}
+
+	out := &collectormetrics.ExportMetricsServiceRequest{}
+	for _, rm := range resources.sorted() {
+		rm.ScopeMetrics = scopes[rm].sorted()
+		for _, sm := range rm.ScopeMetrics {
+			sm.Metrics = metrics[sm].sorted()
+		}
+		out.ResourceMetrics = append(out.ResourceMetrics, rm)
+	}
+	return out
+}
+
+func (o *Options) normalizeLogs(reqs []*collectorlogs.ExportLogsServiceRequest) *collectorlogs.ExportLogsServiceRequest {
+	resources := newGroup[*protologs.ResourceLogs]()
+	scopes := make(map[*protologs.ResourceLogs]*group[*protologs.ScopeLogs])
+
+	for _, req := range reqs {
+		for _, rl := range req.ResourceLogs {
+			res := o.normalizeResource(rl.Resource)
+			target := resources.get(key(res)+rl.SchemaUrl, func() *protologs.ResourceLogs {
+				return &protologs.ResourceLogs{Resource: res, SchemaUrl: rl.SchemaUrl}
+			})
+			if scopes[target] == nil {
+				scopes[target] = newGroup[*protologs.ScopeLogs]()
+			}
+
+			for _, sl := range rl.ScopeLogs {
+				scope := normalizeScope(sl.Scope)
+				scopeTarget := scopes[target].get(key(scope)+sl.SchemaUrl, func() *protologs.ScopeLogs {
+					return &protologs.ScopeLogs{Scope: scope, SchemaUrl: sl.SchemaUrl}
+				})
+				for _, record := range sl.LogRecords {
+					record = proto.Clone(record).(*protologs.LogRecord)
+					record.TimeUnixNano = 0
+					record.ObservedTimeUnixNano = 0
+					record.TraceId = nil
+					record.SpanId = nil
+					sortAttributes(record.Attributes)
+					scopeTarget.LogRecords = append(scopeTarget.LogRecords, record)
+				}
+			}
+		}
+	}
+
+	out := &collectorlogs.ExportLogsServiceRequest{}
+	for _, rl := range resources.sorted() {
+		rl.ScopeLogs = scopes[rl].sorted()
+		for _, sl := range rl.ScopeLogs {
+			sortByKey(sl.LogRecords)
+		}
+		out.ResourceLogs = append(out.ResourceLogs, rl)
+	}
+	return out
+}
+
+func (o *Options) normalizeResource(res *resourcepb.Resource) *resourcepb.Resource {
+	if res == nil {
+		return nil
+	}
+	res = proto.Clone(res).(*resourcepb.Resource)
+	res.Attributes = slices.DeleteFunc(res.Attributes, func(kv *commonpb.KeyValue) bool {
+		return slices.ContainsFunc(o.ignoredResourceAttributes, func(ignored string) bool {
+			if strings.HasSuffix(ignored, ".") {
+				return strings.HasPrefix(kv.Key, ignored)
+			}
+			return kv.Key == ignored
+		})
+	})
+	sortAttributes(res.Attributes)
+	return res
+}
+
+func normalizeScope(scope *commonpb.InstrumentationScope) *commonpb.InstrumentationScope {
+	if scope == nil {
+		return nil
+	}
+	scope = proto.Clone(scope).(*commonpb.InstrumentationScope)
+	sortAttributes(scope.Attributes)
+	return scope
+}
+
+func normalizeSpan(span *prototrace.Span) *prototrace.Span {
+	span = proto.Clone(span).(*prototrace.Span)
+	span.TraceId = nil
+	span.SpanId = nil
+	span.ParentSpanId = nil
+	span.StartTimeUnixNano = 0
+	span.EndTimeUnixNano = 0
+	sortAttributes(span.Attributes)
+	for _, event := range span.Events {
+		event.TimeUnixNano = 0
+		sortAttributes(event.Attributes)
+	}
+	for _, link := range span.Links {
+		link.TraceId = nil
+		link.SpanId = nil
+		sortAttributes(link.Attributes)
+	}
+	return span
+}
+
+// metricDefinition returns a copy of m with the same type and settings but no data points.
+func metricDefinition(m *protometrics.Metric) *protometrics.Metric {
+	definition := &protometrics.Metric{
+		Name:        m.Name,
+		Description: m.Description,
+		Unit:        m.Unit,
+		Metadata:    m.Metadata,
+	}
+	switch data := m.Data.(type) {
+	case *protometrics.Metric_Gauge:
+		definition.Data = &protometrics.Metric_Gauge{Gauge: &protometrics.Gauge{}}
+	case *protometrics.Metric_Sum:
+		definition.Data = &protometrics.Metric_Sum{Sum: &protometrics.Sum{
+			AggregationTemporality: data.Sum.AggregationTemporality,
+			IsMonotonic:            data.Sum.IsMonotonic,
+		}}
+	case *protometrics.Metric_Histogram:
+		definition.Data = &protometrics.Metric_Histogram{Histogram: &protometrics.Histogram{
+			AggregationTemporality: data.Histogram.AggregationTemporality,
+		}}
+	case *protometrics.Metric_ExponentialHistogram:
+		definition.Data = &protometrics.Metric_ExponentialHistogram{ExponentialHistogram: &protometrics.ExponentialHistogram{
+			AggregationTemporality: data.ExponentialHistogram.AggregationTemporality,
+		}}
+	case *protometrics.Metric_Summary:
+		definition.Data = &protometrics.Metric_Summary{Summary: &protometrics.Summary{}}
+	}
+	return definition
+}
+
+// mergeDataPoints appends the normalized data points of src to dst, skipping any dst already has.
+func (o *Options) mergeDataPoints(dst, src *protometrics.Metric) {
+	switch data := dst.Data.(type) {
+	case *protometrics.Metric_Gauge:
+		data.Gauge.DataPoints = mergeUnique(data.Gauge.DataPoints, src.GetGauge().GetDataPoints(), o.normalizeNumberDataPoint)
+	case *protometrics.Metric_Sum:
+		data.Sum.DataPoints = mergeUnique(data.Sum.DataPoints, src.GetSum().GetDataPoints(), o.normalizeNumberDataPoint)
+	case *protometrics.Metric_Histogram:
+		data.Histogram.DataPoints = mergeUnique(data.Histogram.DataPoints, src.GetHistogram().GetDataPoints(), o.normalizeHistogramDataPoint)
+	case *protometrics.Metric_ExponentialHistogram:
+		data.ExponentialHistogram.DataPoints = mergeUnique(data.ExponentialHistogram.DataPoints,
+			src.GetExponentialHistogram().GetDataPoints(), o.normalizeExponentialHistogramDataPoint)
+	case *protometrics.Metric_Summary:
+		data.Summary.DataPoints = mergeUnique(data.Summary.DataPoints, src.GetSummary().GetDataPoints(), o.normalizeSummaryDataPoint)
+	}
+}
+
+func (o *Options) normalizeNumberDataPoint(dp *protometrics.NumberDataPoint) *protometrics.NumberDataPoint {
+	dp = proto.Clone(dp).(*protometrics.NumberDataPoint)
+	dp.StartTimeUnixNano = 0
+	dp.TimeUnixNano = 0
+	dp.Exemplars = nil
+	if !o.keepMetricValues {
+		dp.Value = nil
+	}
+	sortAttributes(dp.Attributes)
+	return dp
+}
+
+func (o *Options) normalizeHistogramDataPoint(dp *protometrics.HistogramDataPoint) *protometrics.HistogramDataPoint {
+	dp = proto.Clone(dp).(*protometrics.HistogramDataPoint)
+	dp.StartTimeUnixNano = 0
+	dp.TimeUnixNano = 0
+	dp.Exemplars = nil
+	if !o.keepMetricValues {
+		dp.Count = 0
+		dp.Sum = nil
+		dp.Min = nil
+		dp.Max = nil
+		dp.BucketCounts = nil
+	}
+	sortAttributes(dp.Attributes)
+	return dp
+}
+
+func (o *Options) normalizeExponentialHistogramDataPoint(dp *protometrics.ExponentialHistogramDataPoint) *protometrics.ExponentialHistogramDataPoint {
+	dp = proto.Clone(dp).(*protometrics.ExponentialHistogramDataPoint)
+	dp.StartTimeUnixNano = 0
+	dp.TimeUnixNano = 0
+	dp.Exemplars = nil
+	if !o.keepMetricValues {
+		dp.Count = 0
+		dp.Sum = nil
+		dp.Min = nil
+		dp.Max = nil
+		dp.Scale = 0
+		dp.ZeroCount = 0
+		dp.Positive = nil
+		dp.Negative = nil
+	}
+	sortAttributes(dp.Attributes)
+	return dp
+}
+
+func (o *Options) normalizeSummaryDataPoint(dp *protometrics.SummaryDataPoint) *protometrics.SummaryDataPoint {
+	dp = proto.Clone(dp).(*protometrics.SummaryDataPoint)
+	dp.StartTimeUnixNano = 0
+	dp.TimeUnixNano = 0
+	if !o.keepMetricValues {
+		dp.Count = 0
+		dp.Sum = 0
+		for _, q := range dp.QuantileValues {
+			q.Value = 0
+		}
+	}
+	sortAttributes(dp.Attributes)
+	return dp
+}
+
+func mergeUnique[T proto.Message](dst, src []T, normalize func(T) T) []T {
+	seen := make(map[string]bool, len(dst))
+	for _, dp := range dst {
+		seen[key(dp)] = true
+	}
+	for _, dp := range src {
+		dp = normalize(dp)
+		k := key(dp)
+		if !seen[k] {
+			seen[k] = true
+			dst = append(dst, dp)
+		}
+	}
+	sortByKey(dst)
+	return dst
+}
+
+func sortAttributes(attrs []*commonpb.KeyValue) {
+	slices.SortStableFunc(attrs, func(a, b *commonpb.KeyValue) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+}
+
+func sortByKey[T proto.Message](items []T) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	})
+}
+
+// key is a deterministic encoding of m, used to group and order messages.
+func key(m proto.Message) string {
+	b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	return string(b)
+}
+
+// group collects values by key and returns them ordered by key.
+type group[T any] struct {
+	items map[string]T
+}
+
+func newGroup[T any]() *group[T] {
+	return &group[T]{items: make(map[string]T)}
+}
+
+func (g *group[T]) get(k string, create func() T) T {
+	if item, ok := g.items[k]; ok {
+		return item
+	}
+	item := create()
+	g.items[k] = item
+	return item
+}
+
+func (g *group[T]) sorted() []T {
+	keys := make([]string, 0, len(g.items))
+	for k := range g.items {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	out := make([]T, len(keys))
+	for idx, k := range keys {
+		out[idx] = g.items[k]
+	}
+	return out
+}
@@ -0,0 +1,185 @@
+package snapshot
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	protometrics "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	prototrace "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestNormalizeIsIndependentOfBatching(t *testing.T) {
+	span := func(name string, id byte, start uint64) *prototrace.Span {
+		return &prototrace.Span{
+			TraceId:           []byte{id, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+			SpanId:            []byte{id, 1, 2, 3, 4, 5, 6, 7},
+			Name:              name,
+			StartTimeUnixNano: start,
+			EndTimeUnixNano:   start + 10,
+			Attributes:        []*commonpb.KeyValue{stringAttribute("z", "last"), stringAttribute("a", "first")},
+		}
+	}
+	resource := func(host string) *resourcepb.Resource {
+		return &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+			stringAttribute("service.name", "checkout"),
+			stringAttribute("host.name", host),
+			stringAttribute("service.instance.id", host),
+		}}
+	}
+
+	oneBatch := &Snapshot{Traces: []*collectortrace.ExportTraceServiceRequest{{
+		ResourceSpans: []*prototrace.ResourceSpans{{
+			Resource:   resource("laptop"),
+			ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{span("charge", 1, 100), span("authorize", 2, 200)}}},
+		}},
+	}}}
+	twoBatches := &Snapshot{Traces: []*collectortrace.ExportTraceServiceRequest{
+		{ResourceSpans: []*prototrace.ResourceSpans{{
+			Resource:   resource("ci-runner"),
+			ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{span("authorize", 8, 5000)}}},
+		}}},
+		{ResourceSpans: []*prototrace.ResourceSpans{{
+			Resource:   resource("ci-runner"),
+			ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{span("charge", 9, 6000)}}},
+		}}},
+	}}
+
+	want, err := Marshal(oneBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Marshal(twoBatches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("batching changed the snapshot (-want +got):\n%s", diff(string(want), string(got)))
+	}
+
+	normalized := Normalize(oneBatch)
+	if len(normalized.Traces) != 1 || len(normalized.Traces[0].ResourceSpans) != 1 {
+		t.Fatalf("expected one merged resource, got %v", normalized.Traces)
+	}
+	rs := normalized.Traces[0].ResourceSpans[0]
+	wantKeys(t, rs.Resource.Attributes, "service.name")
+	for _, s := range rs.ScopeSpans[0].Spans {
+		if s.TraceId != nil || s.SpanId != nil || s.StartTimeUnixNano != 0 || s.EndTimeUnixNano != 0 {
+			t.Errorf("%s: expected IDs and timestamps to be removed, got %v", s.Name, s)
+		}
+		wantKeys(t, s.Attributes, "a", "z")
+	}
+	if oneBatch.Traces[0].ResourceSpans[0].ScopeSpans[0].Spans[0].SpanId == nil {
+		t.Error("Normalize modified its input")
+	}
+}
+
+func TestNormalizeResourceAttributes(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		keys []string
+		want []string
+	}{
+		{
+			name: "defaults remove host-specific attributes",
+			keys: []string{"service.name", "host.name", "os.type", "process.pid", "k8s.pod.name", "telemetry.sdk.version", "telemetry.sdk.language"},
+			want: []string{"service.name", "telemetry.sdk.language"},
+		},
+		{
+			name: "exact key",
+			opts: []Option{WithIgnoredResourceAttributes("deployment.environment")},
+			keys: []string{"service.name", "deployment.environment", "deployment.environment.name"},
+			want: []string{"deployment.environment.name", "service.name"},
+		},
+		{
+			name: "prefix",
+			opts: []Option{WithIgnoredResourceAttributes("cloud.")},
+			keys: []string{"cloud.region", "cloud.provider", "cloudy", "service.name"},
+			want: []string{"cloudy", "service.name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &resourcepb.Resource{}
+			for _, k := range tt.keys {
+				res.Attributes = append(res.Attributes, stringAttribute(k, "value"))
+			}
+			s := &Snapshot{Traces: []*collectortrace.ExportTraceServiceRequest{{
+				ResourceSpans: []*prototrace.ResourceSpans{{Resource: res, ScopeSpans: []*prototrace.ScopeSpans{{Spans: []*prototrace.Span{{Name: "span"}}}}}},
+			}}}
+			wantKeys(t, Normalize(s, tt.opts...).Traces[0].ResourceSpans[0].Resource.Attributes, tt.want...)
+		})
+	}
+}
+
+func TestNormalizeMetricValues(t *testing.T) {
+	export := func(values ...int64) *collectormetrics.ExportMetricsServiceRequest {
+		points := make([]*protometrics.NumberDataPoint, 0, len(values))
+		for idx, v := range values {
+			points = append(points, &protometrics.NumberDataPoint{
+				TimeUnixNano: uint64(idx + 1),
+				Attributes:   []*commonpb.KeyValue{stringAttribute("route", "/cart")},
+				Value:        &protometrics.NumberDataPoint_AsInt{AsInt: v},
+			})
+		}
+		return &collectormetrics.ExportMetricsServiceRequest{ResourceMetrics: []*protometrics.ResourceMetrics{{
+			ScopeMetrics: []*protometrics.ScopeMetrics{{Metrics: []*protometrics.Metric{{
+				Name: "requests",
+				Data: &protometrics.Metric_Sum{Sum: &protometrics.Sum{IsMonotonic: true, DataPoints: points}},
+			}}}},
+		}}}
+	}
+	s := &Snapshot{Metrics: []*collectormetrics.ExportMetricsServiceRequest{export(1, 2), export(3)}}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantValues []int64
+	}{
+		{name: "values are stripped and repeated points merged", wantValues: []int64{0}},
+		{name: "WithMetricValues keeps each distinct value", opts: []Option{WithMetricValues()}, wantValues: []int64{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := Normalize(s, tt.opts...).Metrics[0].ResourceMetrics[0].ScopeMetrics[0].Metrics
+			if len(metrics) != 1 {
+				t.Fatalf("got %d metrics, want the exports merged into 1", len(metrics))
+			}
+			if !metrics[0].GetSum().IsMonotonic {
+				t.Error("expected the sum's definition to be kept")
+			}
+			points := metrics[0].GetSum().DataPoints
+			got := make([]int64, 0, len(points))
+			for _, dp := range points {
+				if dp.TimeUnixNano != 0 {
+					t.Errorf("expected timestamps to be removed, got %d", dp.TimeUnixNano)
+				}
+				got = append(got, dp.GetAsInt())
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.wantValues) {
+				t.Errorf("got values %v, want %v", got, tt.wantValues)
+			}
+		})
+	}
+}
+
+func wantKeys(t *testing.T, attrs []*commonpb.KeyValue, want ...string) {
+	t.Helper()
+	got := make([]string, 0, len(attrs))
+	for _, kv := range attrs {
+		got = append(got, kv.Key)
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("got attributes [%s], want [%s]", strings.Join(got, ", "), strings.Join(want, ", "))
+	}
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
@@ -0,0 +1,67 @@
+// Package snapshot normalizes captured OTLP requests and compares them against golden files,
+// so changes to span names, attributes and metric definitions show up as diffs in review.
+package snapshot
+
+import (
+	"context"
+	"sync"
+
+	relay "github.com/jimschubert/otel-relay/inspector"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	_ relay.Emitter = (*Recorder)(nil)
+)
+
+// Snapshot is a set of captured requests, in arrival order.
+type Snapshot struct {
+	Traces  []*collectortrace.ExportTraceServiceRequest
+	Metrics []*collectormetrics.ExportMetricsServiceRequest
+	Logs    []*collectorlogs.ExportLogsServiceRequest
+}
+
+// Recorder is an inspector.Emitter which keeps every request in memory.
+type Recorder struct {
+	mu       sync.Mutex
+	snapshot Snapshot
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) EmitTrace(_ context.Context, req *collectortrace.ExportTraceServiceRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshot.Traces = append(r.snapshot.Traces, proto.Clone(req).(*collectortrace.ExportTraceServiceRequest))
+	return nil
+}
+
+func (r *Recorder) EmitMetric(_ context.Context, req *collectormetrics.ExportMetricsServiceRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshot.Metrics = append(r.snapshot.Metrics, proto.Clone(req).(*collectormetrics.ExportMetricsServiceRequest))
+	return nil
+}
+
+func (r *Recorder) EmitLog(_ context.Context, req *collectorlogs.ExportLogsServiceRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshot.Logs = append(r.snapshot.Logs, proto.Clone(req).(*collectorlogs.ExportLogsServiceRequest))
+	return nil
+}
+
+// Snapshot returns a copy of everything recorded so far.
+func (r *Recorder) Snapshot() *Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Snapshot{
+		Traces:  append([]*collectortrace.ExportTraceServiceRequest(nil), r.snapshot.Traces...),
+		Metrics: append([]*collectormetrics.ExportMetricsServiceRequest(nil), r.snapshot.Metrics...),
+		Logs:    append([]*collectorlogs.ExportLogsServiceRequest(nil), r.snapshot.Logs...),
+	}
+}